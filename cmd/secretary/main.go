@@ -2,14 +2,16 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
+	_ "time/tzdata"
 
 	"go-secretary/internal/config"
-	"go-secretary/internal/gemini"
 	"go-secretary/internal/jira"
 	"go-secretary/internal/session"
+	"go-secretary/internal/tempo"
 
 	"github.com/pterm/pterm"
 )
@@ -49,21 +51,62 @@ func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer stop()
 
-	jiraClient := jira.NewClient(cfg.JiraURL, cfg.JiraEmail, cfg.JiraAPIToken)
+	if len(os.Args) > 1 && os.Args[1] == "login" {
+		if err := config.RunLogin(ctx, cfg); err != nil {
+			pterm.Error.Println(err.Error())
+			os.Exit(1)
+		}
+		return
+	}
 
-	geminiAssistant, err := gemini.NewAssistant(ctx, cfg.GeminiAPIKey, cfg.GeminiModel)
+	jiraClient, err := cfg.NewJiraClient()
+	if errors.Is(err, config.ErrNotLoggedIn) {
+		pterm.Error.Println("Нужно войти в Jira: sj login")
+		os.Exit(1)
+	}
+	if err != nil {
+		pterm.Error.Println("Ошибка в настройках Jira: " + err.Error())
+		os.Exit(1)
+	}
+	if cfg.JiraInsecureSkipVerify {
+		pterm.Warning.Println("Проверка TLS-сертификата Jira отключена (jira_insecure_skip_verify). Токен может быть перехвачен!")
+	}
+
+	geminiAssistant, err := cfg.NewGeminiAssistant(ctx)
 	if err != nil {
 		pterm.Error.Println("Ошибка при инициализации Gemini: " + err.Error())
 		os.Exit(1)
 	}
 	defer geminiAssistant.Close()
 
-	runner := session.NewRunner(jiraClient, geminiAssistant)
+	jiraClient.SetWorklogTag(jira.WorklogTag{
+		Session: jira.NewSessionID(),
+		Model:   geminiAssistant.Model(),
+		Version: Version,
+	})
+
+	var sink session.WorklogSink = jiraClient
+	if cfg.WorklogBackend == "tempo" {
+		tempoClient := tempo.NewClient(cfg.TempoURL, cfg.TempoAPIToken, jiraClient)
+		tempoClient.SetAttributes(cfg.TempoAttributes)
+		sink = tempoClient
+	}
+
+	runner := session.NewRunner(jiraClient, sink, geminiAssistant)
 
 	var runErr error
-	if len(os.Args) > 1 && os.Args[1] == "period" {
+	switch {
+	case len(os.Args) > 1 && os.Args[1] == "period":
 		runErr = runner.RunPeriod(ctx)
-	} else {
+	case len(os.Args) > 1 && os.Args[1] == "worklogs":
+		runErr = runner.RunWorklogs(ctx)
+	case len(os.Args) > 1 && os.Args[1] == "undo":
+		runErr = runner.RunUndo(ctx)
+	case len(os.Args) > 1 && os.Args[1] == "retry":
+		runErr = runner.RunRetry(ctx)
+	case len(os.Args) > 1 && os.Args[1] == "sync":
+		runErr = runner.RunSync(ctx)
+	default:
 		runErr = runner.Run(ctx)
 	}
 
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-secretary/internal/gemini"
+	"go-secretary/internal/jira"
+)
+
+// NewJiraClient builds a Jira client from the connection settings.
+func (c *Config) NewJiraClient() (*jira.Client, error) {
+	client, err := c.newJiraConnection()
+	if err != nil {
+		return nil, err
+	}
+	if c.JiraAuthType == AuthOAuth {
+		token, err := loadOAuthToken()
+		if err != nil {
+			return nil, err
+		}
+		client.UseOAuth(jira.NewOAuthAuth(c.oauthConfig(), *token, saveOAuthToken))
+	}
+	client.SetConcurrency(c.JiraConcurrency)
+	client.SetTimeout(time.Duration(c.JiraTimeout) * time.Second)
+	if c.Timezone != "" {
+		loc, err := time.LoadLocation(c.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("unknown timezone %q: %w", c.Timezone, err)
+		}
+		client.SetLocation(loc)
+	}
+	if c.IssuePool != nil {
+		if err := c.IssuePool.Validate(); err != nil {
+			return nil, fmt.Errorf("issue_pool: %w", err)
+		}
+		client.SetIssuePool(*c.IssuePool)
+	}
+	if c.WorklogPolicy != nil {
+		if err := c.WorklogPolicy.Validate(); err != nil {
+			return nil, fmt.Errorf("worklog_policy: %w", err)
+		}
+		client.SetWorklogPolicies(*c.WorklogPolicy)
+	}
+	return client, nil
+}
+
+// newJiraConnection builds a Jira client with the TLS and proxy settings
+// but without OAuth, which needs a token from sj login first.
+func (c *Config) newJiraConnection() (*jira.Client, error) {
+	client := jira.NewClient(c.JiraURL, c.JiraEmail, c.JiraAPIToken, jira.AuthMode(c.JiraAuthType))
+	if err := client.SetTLS(c.jiraTLS()); err != nil {
+		return nil, fmt.Errorf("jira TLS: %w", err)
+	}
+	proxy, err := c.JiraProxy.ProxyFunc()
+	if err != nil {
+		return nil, fmt.Errorf("jira_proxy: %w", err)
+	}
+	client.SetProxy(proxy)
+	return client, nil
+}
+
+// NewGeminiAssistant builds the Gemini assistant from the AI settings.
+func (c *Config) NewGeminiAssistant(ctx context.Context) (*gemini.Assistant, error) {
+	cfg, err := c.GeminiConfig()
+	if err != nil {
+		return nil, err
+	}
+	return gemini.NewAssistant(ctx, cfg)
+}
+
+// GeminiConfig returns the settings of the genai client.
+func (c *Config) GeminiConfig() (gemini.Config, error) {
+	httpClient, err := c.GeminiProxy.httpClient()
+	if err != nil {
+		return gemini.Config{}, fmt.Errorf("gemini_proxy: %w", err)
+	}
+	cfg := gemini.Config{
+		Backend:    c.GeminiBackend,
+		Model:      c.GeminiModel,
+		HTTPClient: httpClient,
+		BaseURL:    c.GeminiBaseURL,
+		APIVersion: c.GeminiAPIVersion,
+		Headers:    c.GeminiHeaders,
+	}
+	switch c.GeminiBackend {
+	case gemini.BackendGeminiAPI:
+		cfg.APIKey = c.GeminiAPIKey
+	case gemini.BackendVertexAI:
+		if c.VertexProject == "" {
+			return gemini.Config{}, fmt.Errorf("vertex_project is required for the Vertex AI backend")
+		}
+		cfg.Project = c.VertexProject
+		cfg.Location = c.VertexLocation
+		if cfg.Location == "" {
+			cfg.Location = DefaultVertexLocation
+		}
+		cfg.CredentialsFile = c.VertexCredentials
+	default:
+		return gemini.Config{}, fmt.Errorf("unknown gemini_backend %q, want %s or %s", c.GeminiBackend, gemini.BackendGeminiAPI, gemini.BackendVertexAI)
+	}
+	return cfg, nil
+}
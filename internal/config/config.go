@@ -1,23 +1,60 @@
 package config
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
+
+	"go-secretary/internal/gemini"
+	"go-secretary/internal/jira"
 
 	"github.com/charmbracelet/huh"
 )
 
-const DefaultGeminiModel = "gemini-3-flash-preview"
+const (
+	DefaultGeminiModel    = "gemini-3-flash-preview"
+	DefaultJiraAuthType   = "auto"
+	DefaultWorklogBackend = "jira"
+	DefaultGeminiBackend  = gemini.BackendGeminiAPI
+)
 
 type Config struct {
-	JiraURL      string `json:"jira_url"`
-	JiraEmail    string `json:"jira_email"`
-	JiraAPIToken string `json:"jira_api_token"`
-	GeminiAPIKey string `json:"gemini_api_key"`
-	GeminiModel  string `json:"gemini_model"`
+	JiraURL                string                `json:"jira_url"`
+	JiraAuthType           string                `json:"jira_auth_type"`
+	JiraEmail              string                `json:"jira_email"`
+	JiraAPIToken           string                `json:"jira_api_token"`
+	JiraOAuthClientID      string                `json:"jira_oauth_client_id,omitempty"`
+	JiraOAuthClientSecret  string                `json:"jira_oauth_client_secret,omitempty"`
+	JiraOAuthCallbackPort  int                   `json:"jira_oauth_callback_port,omitempty"`
+	JiraCACert             string                `json:"jira_ca_cert,omitempty"`
+	JiraClientCert         string                `json:"jira_client_cert,omitempty"`
+	JiraClientKey          string                `json:"jira_client_key,omitempty"`
+	JiraInsecureSkipVerify bool                  `json:"jira_insecure_skip_verify,omitempty"`
+	JiraProxy              *ProxyConfig          `json:"jira_proxy,omitempty"`
+	JiraConcurrency        int                   `json:"jira_concurrency,omitempty"`
+	JiraTimeout            int                   `json:"jira_timeout_seconds,omitempty"`
+	Timezone               string                `json:"timezone,omitempty"`
+	IssuePool              *jira.IssuePool       `json:"issue_pool,omitempty"`
+	WorklogPolicy          *jira.WorklogPolicies `json:"worklog_policy,omitempty"`
+	WorklogBackend         string                `json:"worklog_backend"`
+	TempoURL               string                `json:"tempo_url,omitempty"`
+	TempoAPIToken          string                `json:"tempo_api_token,omitempty"`
+	TempoAttributes        map[string]string     `json:"tempo_attributes,omitempty"`
+	GeminiBackend          string                `json:"gemini_backend"`
+	GeminiAPIKey           string                `json:"gemini_api_key"`
+	GeminiModel            string                `json:"gemini_model"`
+	GeminiProxy            *ProxyConfig          `json:"gemini_proxy,omitempty"`
+	GeminiBaseURL          string                `json:"gemini_base_url,omitempty"`
+	GeminiAPIVersion       string                `json:"gemini_api_version,omitempty"`
+	GeminiHeaders          map[string]string     `json:"gemini_headers,omitempty"`
+	VertexProject          string                `json:"vertex_project,omitempty"`
+	VertexLocation         string                `json:"vertex_location,omitempty"`
+	VertexCredentials      string                `json:"vertex_credentials_file,omitempty"`
 }
 
 func GeminiModelOptions() []huh.Option[string] {
@@ -28,13 +65,57 @@ func GeminiModelOptions() []huh.Option[string] {
 	}
 }
 
-func configDir() string {
+func GeminiBackendOptions() []huh.Option[string] {
+	return []huh.Option[string]{
+		huh.NewOption("Google AI Studio: API key", gemini.BackendGeminiAPI),
+		huh.NewOption("Vertex AI: Google Cloud project", gemini.BackendVertexAI),
+	}
+}
+
+func JiraAuthOptions() []huh.Option[string] {
+	return []huh.Option[string]{
+		huh.NewOption("Auto-detect (serverInfo)", "auto"),
+		huh.NewOption("Cloud: email + API token", "basic"),
+		huh.NewOption("Server / Data Center: personal access token", "bearer"),
+		huh.NewOption("Cloud: OAuth 2.0 (sj login)", "oauth"),
+	}
+}
+
+func WorklogBackendOptions() []huh.Option[string] {
+	return []huh.Option[string]{
+		huh.NewOption("Jira worklogs", "jira"),
+		huh.NewOption("Tempo Timesheets", "tempo"),
+	}
+}
+
+// IssuePoolProfileOptions lists the built-in issue pool profiles and the ones
+// defined in the pool.
+func IssuePoolProfileOptions(pool *jira.IssuePool) []huh.Option[string] {
+	labels := map[string]string{
+		jira.ProfileTasks:      "Unresolved tasks, without stories and epics",
+		jira.ProfileAll:        "All unresolved issues",
+		jira.ProfileInProgress: "Issues in progress",
+		jira.ProfileCustom:     "Custom: only the filters below",
+	}
+	var options []huh.Option[string]
+	for _, name := range pool.ProfileNames() {
+		label, ok := labels[name]
+		if _, custom := pool.Profiles[name]; !ok || custom {
+			label = name + " (config file)"
+		}
+		options = append(options, huh.NewOption(label, name))
+	}
+	return options
+}
+
+// Dir is the directory with sj's configuration and local state.
+func Dir() string {
 	home, _ := os.UserHomeDir()
 	return filepath.Join(home, ".secretary")
 }
 
 func configPath() string {
-	return filepath.Join(configDir(), "config.json")
+	return filepath.Join(Dir(), "config.json")
 }
 
 func Exists() bool {
@@ -55,11 +136,20 @@ func LoadFromFile() (*Config, error) {
 	if cfg.GeminiModel == "" {
 		cfg.GeminiModel = DefaultGeminiModel
 	}
+	if cfg.JiraAuthType == "" {
+		cfg.JiraAuthType = DefaultJiraAuthType
+	}
+	if cfg.WorklogBackend == "" {
+		cfg.WorklogBackend = DefaultWorklogBackend
+	}
+	if cfg.GeminiBackend == "" {
+		cfg.GeminiBackend = DefaultGeminiBackend
+	}
 	return &cfg, nil
 }
 
 func Save(cfg *Config) error {
-	if err := os.MkdirAll(configDir(), 0700); err != nil {
+	if err := os.MkdirAll(Dir(), 0700); err != nil {
 		return fmt.Errorf("cannot create config directory: %w", err)
 	}
 	data, err := json.MarshalIndent(cfg, "", "  ")
@@ -77,8 +167,22 @@ func RunSetup() (*Config, error) {
 	if existing.GeminiModel == "" {
 		existing.GeminiModel = DefaultGeminiModel
 	}
+	if existing.JiraAuthType == "" {
+		existing.JiraAuthType = DefaultJiraAuthType
+	}
+	if existing.WorklogBackend == "" {
+		existing.WorklogBackend = DefaultWorklogBackend
+	}
+	if existing.GeminiBackend == "" {
+		existing.GeminiBackend = DefaultGeminiBackend
+	}
 
 	cfg := existing
+	if cfg.IssuePool == nil {
+		pool := jira.DefaultIssuePool()
+		cfg.IssuePool = &pool
+	}
+	poolInput := newIssuePoolInput(cfg.IssuePool)
 
 	form := huh.NewForm(
 		huh.NewGroup(
@@ -92,11 +196,19 @@ func RunSetup() (*Config, error) {
 					}
 					return nil
 				}),
+			huh.NewSelect[string]().
+				Title("Jira Authentication").
+				Options(JiraAuthOptions()...).
+				Value(&cfg.JiraAuthType),
 			huh.NewInput().
 				Title("Jira Email").
+				Description("Not required for Server / Data Center tokens and OAuth").
 				Placeholder("you@company.com").
 				Value(&cfg.JiraEmail).
 				Validate(func(s string) error {
+					if s == "" && (cfg.JiraAuthType == "bearer" || cfg.JiraAuthType == AuthOAuth) {
+						return nil
+					}
 					if !strings.Contains(s, "@") {
 						return fmt.Errorf("must be a valid email address")
 					}
@@ -104,23 +216,63 @@ func RunSetup() (*Config, error) {
 				}),
 		).Title("Jira Connection"),
 
+		tlsGroup(&cfg),
+
+		oauthGroup(&cfg).
+			WithHideFunc(func() bool { return cfg.JiraAuthType != AuthOAuth }),
+
 		huh.NewGroup(
 			huh.NewInput().
 				Title("Jira API Token").
 				EchoMode(huh.EchoModePassword).
 				Value(&cfg.JiraAPIToken),
+		).Title("API Tokens"),
+
+		poolInput.group(),
+
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Worklog Backend").
+				Options(WorklogBackendOptions()...).
+				Value(&cfg.WorklogBackend),
+		).Title("Worklogs"),
+
+		huh.NewGroup(
 			huh.NewInput().
-				Title("Gemini API Key").
+				Title("Tempo API Token").
+				Description("Tempo > Settings > API Integration").
 				EchoMode(huh.EchoModePassword).
-				Value(&cfg.GeminiAPIKey),
-		).Title("API Tokens"),
+				Value(&cfg.TempoAPIToken).
+				Validate(func(s string) error {
+					if s == "" {
+						return fmt.Errorf("token is required for the Tempo backend")
+					}
+					return nil
+				}),
+		).Title("Tempo").
+			WithHideFunc(func() bool { return cfg.WorklogBackend != "tempo" }),
 
 		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Gemini Backend").
+				Options(GeminiBackendOptions()...).
+				Value(&cfg.GeminiBackend),
 			huh.NewSelect[string]().
 				Title("Gemini Model").
 				Options(GeminiModelOptions()...).
 				Value(&cfg.GeminiModel),
 		).Title("AI Model"),
+
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Gemini API Key").
+				EchoMode(huh.EchoModePassword).
+				Value(&cfg.GeminiAPIKey),
+		).Title("Google AI Studio").
+			WithHideFunc(func() bool { return cfg.GeminiBackend != gemini.BackendGeminiAPI }),
+
+		vertexGroup(&cfg).
+			WithHideFunc(func() bool { return cfg.GeminiBackend != gemini.BackendVertexAI }),
 	)
 
 	if err := form.Run(); err != nil {
@@ -128,6 +280,14 @@ func RunSetup() (*Config, error) {
 	}
 
 	cfg.JiraURL = strings.TrimRight(cfg.JiraURL, "/")
+	poolInput.apply(cfg.IssuePool)
+
+	if err := checkTLS(&cfg); err != nil {
+		return nil, err
+	}
+	if err := validateIssuePool(&cfg, poolInput); err != nil {
+		return nil, err
+	}
 
 	if err := Save(&cfg); err != nil {
 		return nil, fmt.Errorf("failed to save config: %w", err)
@@ -136,3 +296,135 @@ func RunSetup() (*Config, error) {
 	fmt.Printf("\nConfig saved to %s\n", configPath())
 	return &cfg, nil
 }
+
+// issuePoolInput holds the wizard's fields for the issue pool. The filters
+// are layered over the chosen profile, so empty fields inherit its defaults.
+// Per-project overrides and custom profiles are only editable in the config
+// file; overrides are kept for projects that remain in the list.
+type issuePoolInput struct {
+	pool                    *jira.IssuePool
+	profile                 string
+	projects                string
+	excludeStatusCategories string
+	issueTypes              string
+	excludeIssueTypes       string
+	extraJQL                string
+}
+
+func newIssuePoolInput(pool *jira.IssuePool) *issuePoolInput {
+	keys := make([]string, len(pool.Projects))
+	for i, p := range pool.Projects {
+		keys[i] = p.Key
+	}
+	profile := pool.Profile
+	if profile == "" {
+		profile = jira.ProfileCustom
+	}
+	return &issuePoolInput{
+		pool:                    pool,
+		profile:                 profile,
+		projects:                strings.Join(keys, ", "),
+		excludeStatusCategories: strings.Join(pool.ExcludeStatusCategories, ", "),
+		issueTypes:              strings.Join(pool.IssueTypes, ", "),
+		excludeIssueTypes:       strings.Join(pool.ExcludeIssueTypes, ", "),
+		extraJQL:                pool.ExtraJQL,
+	}
+}
+
+func (in *issuePoolInput) group() *huh.Group {
+	return huh.NewGroup(
+		huh.NewSelect[string]().
+			Title("Pool Profile").
+			Description("Default filters; the fields below are added on top").
+			Options(IssuePoolProfileOptions(in.pool)...).
+			Value(&in.profile),
+		huh.NewInput().
+			Title("Projects").
+			Description("Comma-separated project keys; empty means all projects").
+			Placeholder("PROJ, OPS").
+			Value(&in.projects),
+		huh.NewInput().
+			Title("Excluded Status Categories").
+			Description("Comma-separated: To Do, In Progress, Done; empty uses the profile").
+			Placeholder("Done").
+			Value(&in.excludeStatusCategories),
+		huh.NewInput().
+			Title("Issue Types").
+			Description("Comma-separated; only these types are offered; empty uses the profile").
+			Placeholder("Task, Bug").
+			Value(&in.issueTypes),
+		huh.NewInput().
+			Title("Excluded Issue Types").
+			Description("Comma-separated; empty uses the profile").
+			Placeholder("Story, Epic").
+			Value(&in.excludeIssueTypes),
+		huh.NewInput().
+			Title("Extra JQL").
+			Description("Optional clause added to the issue query").
+			Placeholder(`component = "Backend"`).
+			Value(&in.extraJQL),
+	).Title("Issue Pool")
+}
+
+func (in *issuePoolInput) apply(pool *jira.IssuePool) {
+	overrides := make(map[string]jira.ProjectPool, len(pool.Projects))
+	for _, p := range pool.Projects {
+		overrides[strings.ToUpper(p.Key)] = p
+	}
+
+	pool.Projects = nil
+	for _, key := range splitList(in.projects) {
+		project, ok := overrides[strings.ToUpper(key)]
+		if !ok {
+			project = jira.ProjectPool{Key: key}
+		}
+		pool.Projects = append(pool.Projects, project)
+	}
+	pool.Profile = in.profile
+	pool.ExcludeStatusCategories = splitList(in.excludeStatusCategories)
+	pool.IssueTypes = splitList(in.issueTypes)
+	pool.ExcludeIssueTypes = splitList(in.excludeIssueTypes)
+	pool.ExtraJQL = strings.TrimSpace(in.extraJQL)
+}
+
+// validateIssuePool runs the pool query against Jira and asks again while
+// Jira rejects it. Connection problems only produce a warning.
+func validateIssuePool(cfg *Config, in *issuePoolInput) error {
+	for {
+		client, err := cfg.NewJiraClient()
+		if errors.Is(err, ErrNotLoggedIn) {
+			fmt.Println("\nRun sj login to finish the OAuth setup; the issue pool will be checked on first use.")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		err = client.ValidateJQL(ctx, cfg.IssuePool.JQL())
+		cancel()
+
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, jira.ErrInvalidJQL) {
+			fmt.Printf("\nWarning: could not check the issue pool: %v\n", err)
+			return nil
+		}
+
+		fmt.Printf("\nJira rejected the issue pool query: %v\n\n", err)
+		if err := huh.NewForm(in.group()).Run(); err != nil {
+			return err
+		}
+		in.apply(cfg.IssuePool)
+	}
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+
+	"go-secretary/internal/jira"
+
+	"github.com/charmbracelet/huh"
+)
+
+// AuthOAuth is the jira_auth_type of OAuth 2.0 (3LO) logins made with sj login.
+const AuthOAuth = "oauth"
+
+// ErrNotLoggedIn means OAuth is configured but sj login has not been run.
+var ErrNotLoggedIn = errors.New("not logged in to Jira, run sj login")
+
+func oauthTokenPath() string {
+	return filepath.Join(Dir(), "oauth.json")
+}
+
+func loadOAuthToken() (*jira.OAuthToken, error) {
+	data, err := os.ReadFile(oauthTokenPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotLoggedIn
+	}
+	if err != nil {
+		return nil, err
+	}
+	var token jira.OAuthToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, fmt.Errorf("invalid OAuth token file: %w", err)
+	}
+	return &token, nil
+}
+
+func saveOAuthToken(token jira.OAuthToken) error {
+	if err := os.MkdirAll(Dir(), 0700); err != nil {
+		return fmt.Errorf("cannot create config directory: %w", err)
+	}
+	data, err := json.MarshalIndent(token, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(oauthTokenPath(), data, 0600)
+}
+
+func (c *Config) oauthConfig() jira.OAuthConfig {
+	port := c.JiraOAuthCallbackPort
+	if port == 0 {
+		port = jira.DefaultOAuthCallbackPort
+	}
+	return jira.OAuthConfig{
+		ClientID:     c.JiraOAuthClientID,
+		ClientSecret: c.JiraOAuthClientSecret,
+		CallbackPort: port,
+	}
+}
+
+func oauthGroup(cfg *Config) *huh.Group {
+	return huh.NewGroup(
+		huh.NewInput().
+			Title("OAuth Client ID").
+			Description(fmt.Sprintf("developer.atlassian.com > your app; callback URL http://127.0.0.1:%d/callback", cfg.oauthConfig().CallbackPort)).
+			Value(&cfg.JiraOAuthClientID).
+			Validate(func(s string) error {
+				if s == "" {
+					return fmt.Errorf("client ID is required for OAuth")
+				}
+				return nil
+			}),
+		huh.NewInput().
+			Title("OAuth Client Secret").
+			EchoMode(huh.EchoModePassword).
+			Value(&cfg.JiraOAuthClientSecret),
+	).Title("Jira OAuth")
+}
+
+// RunLogin signs in to Jira Cloud with OAuth 2.0 (sj login) and switches the
+// config to OAuth if it used another auth type.
+func RunLogin(ctx context.Context, cfg *Config) error {
+	if cfg.JiraOAuthClientID == "" {
+		if err := huh.NewForm(oauthGroup(cfg)).Run(); err != nil {
+			return err
+		}
+	}
+
+	client, err := cfg.newJiraConnection()
+	if err != nil {
+		return err
+	}
+	token, err := client.Login(ctx, cfg.oauthConfig(), func(authURL string) {
+		fmt.Println("Open this URL to authorize sj:")
+		fmt.Println()
+		fmt.Println(authURL)
+		fmt.Println()
+		openBrowser(authURL)
+	})
+	if err != nil {
+		return fmt.Errorf("OAuth login failed: %w", err)
+	}
+	if err := saveOAuthToken(*token); err != nil {
+		return fmt.Errorf("failed to save OAuth token: %w", err)
+	}
+
+	cfg.JiraAuthType = AuthOAuth
+	if err := Save(cfg); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	fmt.Printf("Logged in to %s\n", token.SiteURL)
+	return nil
+}
+
+// openBrowser tries to open the URL; the URL is printed anyway in case it fails.
+func openBrowser(u string) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", u)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", u)
+	default:
+		cmd = exec.Command("xdg-open", u)
+	}
+	if err := cmd.Start(); err == nil {
+		go cmd.Wait()
+	}
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// ProxyDirect as a proxy URL bypasses both the config and the environment.
+const ProxyDirect = "direct"
+
+// ProxyConfig routes the traffic of one service. URL is an http://,
+// https:// or socks5:// proxy, ProxyDirect, or empty to use HTTP_PROXY and
+// HTTPS_PROXY from the environment. NoProxy replaces NO_PROXY: hosts,
+// domains (".corp.local") and CIDRs that are always reached directly.
+type ProxyConfig struct {
+	URL     string   `json:"url,omitempty"`
+	NoProxy []string `json:"no_proxy,omitempty"`
+}
+
+// ProxyFunc returns the proxy selector for http.Transport. A nil config
+// keeps the environment proxies.
+func (p *ProxyConfig) ProxyFunc() (func(*http.Request) (*url.URL, error), error) {
+	if p == nil {
+		return http.ProxyFromEnvironment, nil
+	}
+	if p.URL == ProxyDirect {
+		return nil, nil
+	}
+
+	cfg := httpproxy.FromEnvironment()
+	if p.URL != "" {
+		u, err := url.Parse(p.URL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		switch u.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return nil, fmt.Errorf("unsupported proxy scheme %q, want http, https or socks5", u.Scheme)
+		}
+		if u.Host == "" {
+			return nil, fmt.Errorf("proxy URL %q has no host", p.URL)
+		}
+		cfg.HTTPProxy, cfg.HTTPSProxy = p.URL, p.URL
+	}
+	if len(p.NoProxy) > 0 {
+		cfg.NoProxy = strings.Join(p.NoProxy, ",")
+	}
+
+	proxy := cfg.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxy(req.URL)
+	}, nil
+}
+
+// httpClient is an http.Client that uses the proxy and otherwise behaves
+// like http.DefaultClient.
+func (p *ProxyConfig) httpClient() (*http.Client, error) {
+	proxy, err := p.ProxyFunc()
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxy
+	return &http.Client{Transport: transport}, nil
+}
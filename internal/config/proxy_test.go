@@ -0,0 +1,57 @@
+package config
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestProxyFunc(t *testing.T) {
+	t.Setenv("HTTPS_PROXY", "http://env-proxy:3128")
+	t.Setenv("HTTP_PROXY", "")
+	t.Setenv("NO_PROXY", "")
+
+	tests := []struct {
+		name  string
+		proxy *ProxyConfig
+		url   string
+		want  string
+	}{
+		{"nil uses environment", nil, "https://jira.corp.local", "http://env-proxy:3128"},
+		{"empty URL uses environment", &ProxyConfig{}, "https://jira.corp.local", "http://env-proxy:3128"},
+		{"direct", &ProxyConfig{URL: ProxyDirect}, "https://jira.corp.local", ""},
+		{"socks5", &ProxyConfig{URL: "socks5://127.0.0.1:7890"}, "https://generativelanguage.googleapis.com", "socks5://127.0.0.1:7890"},
+		{"no_proxy domain", &ProxyConfig{URL: "http://127.0.0.1:7890", NoProxy: []string{".corp.local"}}, "https://jira.corp.local", ""},
+		{"no_proxy other host", &ProxyConfig{URL: "http://127.0.0.1:7890", NoProxy: []string{".corp.local"}}, "https://example.com", "http://127.0.0.1:7890"},
+		{"no_proxy CIDR", &ProxyConfig{NoProxy: []string{"10.0.0.0/8"}}, "https://10.1.2.3", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fn, err := tt.proxy.ProxyFunc()
+			if err != nil {
+				t.Fatalf("ProxyFunc() error: %v", err)
+			}
+			got := ""
+			if fn != nil {
+				req, _ := http.NewRequest(http.MethodGet, tt.url, nil)
+				u, err := fn(req)
+				if err != nil {
+					t.Fatalf("proxy(%s) error: %v", tt.url, err)
+				}
+				if u != nil {
+					got = u.String()
+				}
+			}
+			if got != tt.want {
+				t.Errorf("proxy(%s) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProxyFuncRejectsInvalidURL(t *testing.T) {
+	for _, raw := range []string{"ftp://proxy:21", "127.0.0.1:7890", "http://"} {
+		if _, err := (&ProxyConfig{URL: raw}).ProxyFunc(); err == nil {
+			t.Errorf("ProxyFunc(%q) error = nil", raw)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"go-secretary/internal/jira"
+
+	"github.com/charmbracelet/huh"
+)
+
+func (c *Config) jiraTLS() jira.TLSConfig {
+	return jira.TLSConfig{
+		CAFile:             c.JiraCACert,
+		CertFile:           c.JiraClientCert,
+		KeyFile:            c.JiraClientKey,
+		InsecureSkipVerify: c.JiraInsecureSkipVerify,
+	}
+}
+
+func tlsGroup(cfg *Config) *huh.Group {
+	return huh.NewGroup(
+		huh.NewInput().
+			Title("CA Bundle").
+			Description("PEM file with your company's root CA; empty uses the system roots").
+			Placeholder("/etc/ssl/certs/corp-ca.pem").
+			Value(&cfg.JiraCACert).
+			Validate(fileExists),
+		huh.NewInput().
+			Title("Client Certificate").
+			Description("PEM file, only if Jira requires mutual TLS").
+			Value(&cfg.JiraClientCert).
+			Validate(fileExists),
+		huh.NewInput().
+			Title("Client Key").
+			Value(&cfg.JiraClientKey).
+			Validate(func(s string) error {
+				if (s == "") != (cfg.JiraClientCert == "") {
+					return fmt.Errorf("client certificate and key must be set together")
+				}
+				return fileExists(s)
+			}),
+		huh.NewConfirm().
+			Title("Skip certificate verification?").
+			Description("INSECURE: anyone on the network can read your Jira token").
+			Value(&cfg.JiraInsecureSkipVerify),
+	).Title("Jira TLS (optional)")
+}
+
+func fileExists(path string) error {
+	if path == "" {
+		return nil
+	}
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("cannot read %s", path)
+	}
+	return nil
+}
+
+// checkTLS connects to Jira and asks for the TLS settings again while the
+// handshake fails. Other connection problems are left to later checks.
+func checkTLS(cfg *Config) error {
+	for {
+		client := jira.NewClient(cfg.JiraURL, "", "", jira.AuthMode(cfg.JiraAuthType))
+		if proxy, err := cfg.JiraProxy.ProxyFunc(); err == nil {
+			client.SetProxy(proxy)
+		}
+		err := client.SetTLS(cfg.jiraTLS())
+		if err == nil {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			_, err = client.ServerInfo(ctx)
+			cancel()
+			if !jira.IsTLSError(err) {
+				return nil
+			}
+		}
+
+		fmt.Printf("\nTLS connection to Jira failed: %v\n", err)
+		fmt.Println("Set the CA bundle of your company, or a client certificate if Jira requires one.")
+		fmt.Println()
+		if err := huh.NewForm(tlsGroup(cfg)).Run(); err != nil {
+			return err
+		}
+	}
+}
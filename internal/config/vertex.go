@@ -0,0 +1,37 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/huh"
+)
+
+// DefaultVertexLocation serves Gemini models from any region.
+const DefaultVertexLocation = "global"
+
+func vertexGroup(cfg *Config) *huh.Group {
+	return huh.NewGroup(
+		huh.NewInput().
+			Title("Google Cloud Project").
+			Description("Project ID with the Vertex AI API enabled").
+			Placeholder("my-project-123").
+			Value(&cfg.VertexProject).
+			Validate(func(s string) error {
+				if s == "" {
+					return fmt.Errorf("project is required for Vertex AI")
+				}
+				return nil
+			}),
+		huh.NewInput().
+			Title("Location").
+			Description("Vertex AI region; empty means "+DefaultVertexLocation).
+			Placeholder("europe-west4").
+			Value(&cfg.VertexLocation),
+		huh.NewInput().
+			Title("Service Account Key").
+			Description("JSON key file; empty uses Application Default Credentials (gcloud auth application-default login)").
+			Placeholder("/path/to/sj-vertex.json").
+			Value(&cfg.VertexCredentials).
+			Validate(fileExists),
+	).Title("Vertex AI")
+}
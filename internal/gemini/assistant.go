@@ -4,12 +4,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
 	"go-secretary/internal/jira"
 	"go-secretary/internal/timeparse"
 
+	"cloud.google.com/go/auth"
+	"cloud.google.com/go/auth/credentials"
+	"cloud.google.com/go/auth/httptransport"
 	"github.com/pterm/pterm"
 	"google.golang.org/genai"
 )
@@ -20,22 +24,133 @@ type Assistant struct {
 	model  string
 }
 
-func NewAssistant(ctx context.Context, apiKey, model string) (*Assistant, error) {
-	client, err := genai.NewClient(ctx, &genai.ClientConfig{
-		APIKey:  apiKey,
-		Backend: genai.BackendGeminiAPI,
-	})
+// Backends of the genai client.
+const (
+	BackendGeminiAPI = "gemini"
+	BackendVertexAI  = "vertex"
+)
+
+// Config holds the settings of the genai client. A nil HTTPClient uses
+// http.DefaultClient. BaseURL, APIVersion and Headers point the client at a
+// gateway or a mock server; empty values keep the genai defaults.
+//
+// The Gemini API backend authenticates with APIKey. Vertex AI uses Project
+// and Location with the service account in CredentialsFile, or Application
+// Default Credentials when it is empty.
+type Config struct {
+	Backend         string
+	APIKey          string
+	Model           string
+	Project         string
+	Location        string
+	CredentialsFile string
+	HTTPClient      *http.Client
+	BaseURL         string
+	APIVersion      string
+	Headers         map[string]string
+}
+
+func (cfg Config) httpOptions() genai.HTTPOptions {
+	opts := genai.HTTPOptions{
+		BaseURL:    cfg.BaseURL,
+		APIVersion: cfg.APIVersion,
+	}
+	if len(cfg.Headers) > 0 {
+		opts.Headers = make(http.Header, len(cfg.Headers))
+		for name, value := range cfg.Headers {
+			opts.Headers.Set(name, value)
+		}
+	}
+	return opts
+}
+
+func NewAssistant(ctx context.Context, cfg Config) (*Assistant, error) {
+	client, err := newClient(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Assistant{client: client, model: cfg.Model}, nil
+}
+
+// Reconnect switches the assistant to new settings. The current chat is
+// dropped, as after SetModel.
+func (a *Assistant) Reconnect(ctx context.Context, cfg Config) error {
+	client, err := newClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	a.client = client
+	a.model = cfg.Model
+	a.chat = nil
+	return nil
+}
+
+func newClient(ctx context.Context, cfg Config) (*genai.Client, error) {
+	cc := &genai.ClientConfig{
+		Backend:     genai.BackendGeminiAPI,
+		APIKey:      cfg.APIKey,
+		HTTPClient:  cfg.HTTPClient,
+		HTTPOptions: cfg.httpOptions(),
+	}
+	if cfg.Backend == BackendVertexAI {
+		httpClient, creds, err := vertexHTTPClient(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		cc.Backend = genai.BackendVertexAI
+		cc.APIKey = ""
+		cc.Project = cfg.Project
+		cc.Location = cfg.Location
+		cc.Credentials = creds
+		cc.HTTPClient = httpClient
+	}
+
+	client, err := genai.NewClient(ctx, cc)
 	if err != nil {
 		return nil, fmt.Errorf("create genai client: %w", err)
 	}
-	return &Assistant{client: client, model: model}, nil
+	return client, nil
 }
 
-func (a *Assistant) StartConversation(ctx context.Context, issues []jira.Issue, loggedSeconds int, date string) (string, error) {
-	systemPrompt := buildSystemPrompt(issues, loggedSeconds, date)
+// vertexScope is the OAuth scope of Vertex AI requests.
+const vertexScope = "https://www.googleapis.com/auth/cloud-platform"
+
+// vertexHTTPClient authorizes requests with Google Cloud credentials on top
+// of cfg.HTTPClient, so Vertex AI calls and token refreshes share its proxy.
+func vertexHTTPClient(ctx context.Context, cfg Config) (*http.Client, *auth.Credentials, error) {
+	base := cfg.HTTPClient
+	if base == nil {
+		base = http.DefaultClient
+	}
+	creds, err := credentials.DetectDefault(&credentials.DetectOptions{
+		Scopes:          []string{vertexScope},
+		CredentialsFile: cfg.CredentialsFile,
+		Client:          base,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("load Google Cloud credentials: %w", err)
+	}
+
+	headers := http.Header{}
+	if quotaProject, err := creds.QuotaProjectID(ctx); err == nil && quotaProject != "" {
+		headers.Set("X-Goog-User-Project", quotaProject)
+	}
+	client, err := httptransport.NewClient(&httptransport.Options{
+		Credentials:      creds,
+		BaseRoundTripper: base.Transport,
+		Headers:          headers,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("create Vertex AI HTTP client: %w", err)
+	}
+	return client, creds, nil
+}
+
+func (a *Assistant) StartConversation(ctx context.Context, issues []jira.Issue, loggedSeconds, requiredSeconds int, date string) (string, error) {
+	systemPrompt := buildSystemPrompt(issues, loggedSeconds, requiredSeconds, date)
 
 	var err error
-	a.chat, err = a.client.Chats.Create(ctx, "models/"+a.model, &genai.GenerateContentConfig{
+	a.chat, err = a.client.Chats.Create(ctx, a.model, &genai.GenerateContentConfig{
 		SystemInstruction: genai.NewContentFromText(systemPrompt, genai.RoleUser),
 	}, nil)
 	if err != nil {
@@ -152,22 +267,10 @@ func (a *Assistant) Close() {
 	// genai client doesn't require explicit close
 }
 
-func formatDuration(seconds int) string {
-	h := seconds / 3600
-	m := (seconds % 3600) / 60
-	if h > 0 && m > 0 {
-		return fmt.Sprintf("%dh %dm", h, m)
-	}
-	if h > 0 {
-		return fmt.Sprintf("%dh", h)
-	}
-	return fmt.Sprintf("%dm", m)
-}
-
-func buildSystemPrompt(issues []jira.Issue, loggedSeconds int, date string) string {
+func buildSystemPrompt(issues []jira.Issue, loggedSeconds, requiredSeconds int, date string) string {
 	var sb strings.Builder
 	for _, issue := range issues {
-		fmt.Fprintf(&sb, "- %s: %s\n", issue.Key, issue.Summary)
+		sb.WriteString(describeIssue(issue))
 	}
 
 	dayLabel := "сегодня"
@@ -177,17 +280,18 @@ func buildSystemPrompt(issues []jira.Issue, loggedSeconds int, date string) stri
 		dayLabelAccusative = "чем ты занимался " + date
 	}
 
-	remainingSeconds := 8*3600 - loggedSeconds
+	workday := timeparse.Format(requiredSeconds)
+	remainingSeconds := requiredSeconds - loggedSeconds
 	if remainingSeconds < 0 {
 		remainingSeconds = 0
 	}
 
 	timeInfo := ""
 	if loggedSeconds > 0 {
-		timeInfo = fmt.Sprintf("\nУЖЕ ЗАЛОГИРОВАНО %s: %s\nОСТАЛОСЬ ЗАЛОГИРОВАТЬ: %s (рабочий день = 8h)\n",
-			strings.ToUpper(dayLabel), formatDuration(loggedSeconds), formatDuration(remainingSeconds))
+		timeInfo = fmt.Sprintf("\nУЖЕ ЗАЛОГИРОВАНО %s: %s\nОСТАЛОСЬ ЗАЛОГИРОВАТЬ: %s (рабочий день = %s)\n",
+			strings.ToUpper(dayLabel), timeparse.Format(loggedSeconds), timeparse.Format(remainingSeconds), workday)
 	} else {
-		timeInfo = fmt.Sprintf("\n%s ЕЩЁ НИЧЕГО НЕ ЗАЛОГИРОВАНО. Рабочий день = 8h.\n", strings.ToUpper(dayLabel))
+		timeInfo = fmt.Sprintf("\n%s ЕЩЁ НИЧЕГО НЕ ЗАЛОГИРОВАНО. Рабочий день = %s.\n", strings.ToUpper(dayLabel), workday)
 	}
 
 	return fmt.Sprintf("Ты - дружелюбный AI-ассистент для логирования времени работы в Jira Tempo.\n\n"+
@@ -201,14 +305,15 @@ func buildSystemPrompt(issues []jira.Issue, loggedSeconds int, date string) stri
 		"- Пользователь описывает активности своими словами.\n\n"+
 		"ШАГ 2 — Сопоставление с задачами\n"+
 		"- На основе рассказа предложи, к каким задачам из списка относится каждая активность.\n"+
+		"- Используй проект, тип, эпик/родителя, компоненты и метки задач, чтобы точнее сопоставлять работу.\n"+
 		"- Если пользователь явно упомянул ключ задачи (например PROJ-456), которого нет в списке — прими его как есть.\n"+
 		"- Если что-то неясно (не понятно к какой задаче отнести) — уточни.\n"+
 		"- Дождись подтверждения от пользователя, что сопоставление верное.\n\n"+
 		"ШАГ 3 — Сколько времени?\n"+
 		"- Спроси, сколько времени пользователь потратил на каждую из задач.\n"+
 		"- Формат времени: 2h, 30m, 2h 30m, 1.5h.\n"+
-		"- Учитывай уже залогированное время — суммарно за день должно быть ровно 8h.\n"+
-		"- Если сумма нового времени + уже залогированного не равна 8h, обрати на это внимание пользователя.\n\n"+
+		"- Учитывай уже залогированное время — суммарно за день должно быть ровно %s.\n"+
+		"- Если сумма нового времени + уже залогированного не равна %s, обрати на это внимание пользователя.\n\n"+
 		"ШАГ 4 — Итог\n"+
 		"- Покажи финальную сводку в виде списка:\n"+
 		"  Задача | Время | Что делал\n"+
@@ -232,7 +337,49 @@ func buildSystemPrompt(issues []jira.Issue, loggedSeconds int, date string) stri
 		"  \"ready_to_submit\": true\n"+
 		"}\n"+
 		"```\n"+
-		"Начинай диалог!", dayLabel, timeInfo, sb.String(), dayLabelAccusative)
+		"Начинай диалог!", dayLabel, timeInfo, sb.String(), dayLabelAccusative, workday, workday)
+}
+
+// describeIssue renders one issue line of the system prompt with the context
+// that helps to match activities: type, project, parent, labels and estimates.
+func describeIssue(issue jira.Issue) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "- %s: %s", issue.Key, issue.Summary)
+
+	var details []string
+	if issue.IssueType != "" {
+		details = append(details, "тип: "+issue.IssueType)
+	}
+	if issue.Project != "" {
+		details = append(details, "проект: "+issue.Project)
+	}
+	switch {
+	case issue.ParentSummary != "":
+		details = append(details, fmt.Sprintf("родитель: %s %q", issue.ParentKey, issue.ParentSummary))
+	case issue.ParentKey != "":
+		details = append(details, "родитель: "+issue.ParentKey)
+	}
+	if len(issue.Components) > 0 {
+		details = append(details, "компоненты: "+strings.Join(issue.Components, ", "))
+	}
+	if len(issue.Labels) > 0 {
+		details = append(details, "метки: "+strings.Join(issue.Labels, ", "))
+	}
+	if issue.Assignee != "" {
+		details = append(details, "исполнитель: "+issue.Assignee)
+	}
+	if issue.OriginalEstimateSeconds > 0 || issue.TimeSpentSeconds > 0 {
+		details = append(details, fmt.Sprintf("оценка: %s, потрачено: %s, осталось: %s",
+			timeparse.Format(issue.OriginalEstimateSeconds),
+			timeparse.Format(issue.TimeSpentSeconds),
+			timeparse.Format(issue.RemainingEstimateSeconds)))
+	}
+
+	if len(details) > 0 {
+		sb.WriteString(" (" + strings.Join(details, "; ") + ")")
+	}
+	sb.WriteString("\n")
+	return sb.String()
 }
 
 func extractText(resp *genai.GenerateContentResponse) string {
@@ -0,0 +1,154 @@
+package gemini
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go-secretary/internal/jira"
+)
+
+func TestDescribeIssue(t *testing.T) {
+	tests := []struct {
+		name  string
+		issue jira.Issue
+		want  string
+	}{
+		{
+			"bare",
+			jira.Issue{Key: "PROJ-1", Summary: "Fix login"},
+			"- PROJ-1: Fix login\n",
+		},
+		{
+			"full",
+			jira.Issue{
+				Key:                      "PROJ-2",
+				Summary:                  "Add export",
+				Project:                  "PROJ",
+				IssueType:                "Task",
+				ParentKey:                "PROJ-100",
+				ParentSummary:            "Reports",
+				Labels:                   []string{"backend"},
+				Components:               []string{"API"},
+				OriginalEstimateSeconds:  8 * 3600,
+				TimeSpentSeconds:         5 * 3600,
+				RemainingEstimateSeconds: 3 * 3600,
+			},
+			`- PROJ-2: Add export (тип: Task; проект: PROJ; родитель: PROJ-100 "Reports"; компоненты: API; метки: backend; оценка: 8h, потрачено: 5h, осталось: 3h)` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := describeIssue(tt.issue); got != tt.want {
+				t.Errorf("describeIssue() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssistantUsesGateway(t *testing.T) {
+	var path, team string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		team = r.Header.Get("X-Team")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"candidates":[{"content":{"role":"model","parts":[{"text":"Привет! Над чем работал?"}]}}]}`))
+	}))
+	defer srv.Close()
+
+	a, err := NewAssistant(context.Background(), Config{
+		APIKey:     "gateway",
+		Model:      "gemini-test",
+		BaseURL:    srv.URL,
+		APIVersion: "v1",
+		Headers:    map[string]string{"X-Team": "backend"},
+	})
+	if err != nil {
+		t.Fatalf("NewAssistant() error: %v", err)
+	}
+	defer a.Close()
+
+	reply, err := a.StartConversation(context.Background(), nil, 0, 8*3600, "2026-10-16")
+	if err != nil {
+		t.Fatalf("StartConversation() error: %v", err)
+	}
+	if reply != "Привет! Над чем работал?" {
+		t.Errorf("reply = %q", reply)
+	}
+	if path != "/v1/models/gemini-test:generateContent" {
+		t.Errorf("path = %q", path)
+	}
+	if team != "backend" {
+		t.Errorf("X-Team header = %q, want backend", team)
+	}
+}
+
+func TestAssistantVertexServiceAccount(t *testing.T) {
+	var path, authorization string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"sa-token","token_type":"Bearer","expires_in":3600}`))
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		authorization = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"candidates":[{"content":{"role":"model","parts":[{"text":"Привет!"}]}}]}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	a, err := NewAssistant(context.Background(), Config{
+		Backend:         BackendVertexAI,
+		Model:           "gemini-test",
+		Project:         "acme",
+		Location:        "europe-west4",
+		CredentialsFile: writeServiceAccount(t, srv.URL+"/token"),
+		BaseURL:         srv.URL,
+	})
+	if err != nil {
+		t.Fatalf("NewAssistant() error: %v", err)
+	}
+
+	if _, err := a.StartConversation(context.Background(), nil, 0, 8*3600, "2026-10-16"); err != nil {
+		t.Fatalf("StartConversation() error: %v", err)
+	}
+	want := "/v1beta1/projects/acme/locations/europe-west4/publishers/google/models/gemini-test:generateContent"
+	if path != want {
+		t.Errorf("path = %q, want %q", path, want)
+	}
+	if authorization != "Bearer sa-token" {
+		t.Errorf("Authorization = %q", authorization)
+	}
+}
+
+func writeServiceAccount(t *testing.T, tokenURL string) string {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(map[string]string{
+		"type":           "service_account",
+		"project_id":     "acme",
+		"private_key_id": "key-1",
+		"private_key":    string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})),
+		"client_email":   "sj@acme.iam.gserviceaccount.com",
+		"token_uri":      tokenURL,
+	})
+	path := filepath.Join(t.TempDir(), "service-account.json")
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
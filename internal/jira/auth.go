@@ -0,0 +1,102 @@
+package jira
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type AuthMode string
+
+const (
+	AuthAuto   AuthMode = "auto"
+	AuthBasic  AuthMode = "basic"
+	AuthBearer AuthMode = "bearer"
+)
+
+// Authenticator signs outgoing Jira requests.
+type Authenticator interface {
+	Authenticate(req *http.Request) error
+}
+
+// BasicAuth is used by Jira Cloud: account email + API token.
+type BasicAuth struct {
+	Email    string
+	APIToken string
+}
+
+func (a BasicAuth) Authenticate(req *http.Request) error {
+	req.SetBasicAuth(a.Email, a.APIToken)
+	return nil
+}
+
+// BearerAuth is used by Jira Server / Data Center personal access tokens.
+type BearerAuth struct {
+	Token string
+}
+
+func (a BearerAuth) Authenticate(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+a.Token)
+	return nil
+}
+
+// ServerInfo returns deployment details. The endpoint is called anonymously
+// so it can be used before the auth mode is known.
+func (c *Client) ServerInfo(ctx context.Context) (*ServerInfo, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/rest/api/2/serverInfo", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var info ServerInfo
+	if err := c.do(req, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// authenticator returns the signer for requests, detecting it on first use.
+// A detection that could not reach the server is not cached, so a cancelled
+// or timed-out first request does not decide the mode for the whole run.
+func (c *Client) authenticator(ctx context.Context) Authenticator {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+	if c.auth != nil {
+		return c.auth
+	}
+	auth, ok := c.resolveAuth(ctx)
+	if ok {
+		c.auth = auth
+	}
+	return auth
+}
+
+// resolveAuth picks the authenticator for the configured mode. ok is false
+// when auto-detection had to guess because serverInfo could not be reached.
+func (c *Client) resolveAuth(ctx context.Context) (auth Authenticator, ok bool) {
+	basic := BasicAuth{Email: c.email, APIToken: c.apiToken}
+	bearer := BearerAuth{Token: c.apiToken}
+
+	switch c.authMode {
+	case AuthBasic:
+		return basic, true
+	case AuthBearer:
+		return bearer, true
+	}
+
+	info, err := c.ServerInfo(ctx)
+	if err == nil {
+		if info.IsCloud() {
+			return basic, true
+		}
+		return bearer, true
+	}
+
+	// serverInfo may be blocked for anonymous users; fall back to the host
+	// name. Only a response from Jira makes the guess final.
+	ok = statusOf(err) != 0
+	if u, err := url.Parse(c.baseURL); err == nil && strings.HasSuffix(u.Hostname(), ".atlassian.net") {
+		return basic, ok
+	}
+	return bearer, ok
+}
@@ -0,0 +1,66 @@
+package jira
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResolveAuth(t *testing.T) {
+	tests := []struct {
+		name       string
+		mode       AuthMode
+		deployment string
+		wantBasic  bool
+	}{
+		{"explicit basic", AuthBasic, "Server", true},
+		{"explicit bearer", AuthBearer, "Cloud", false},
+		{"auto cloud", AuthAuto, "Cloud", true},
+		{"auto server", AuthAuto, "Server", false},
+		{"auto data center", AuthAuto, "DataCenter", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") != "" {
+					t.Errorf("serverInfo must be called anonymously")
+				}
+				w.Write([]byte(`{"deploymentType":"` + tt.deployment + `"}`))
+			}))
+			defer srv.Close()
+
+			c := NewClient(srv.URL, "me@example.com", "token", tt.mode)
+			_, isBasic := c.authenticator(context.Background()).(BasicAuth)
+			if isBasic != tt.wantBasic {
+				t.Errorf("basic = %v, want %v", isBasic, tt.wantBasic)
+			}
+		})
+	}
+}
+
+func TestResolveAuthRetriesAfterCancel(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`{"deploymentType":"Cloud"}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "me@example.com", "token", AuthAuto)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, isBasic := c.authenticator(ctx).(BasicAuth); isBasic {
+		t.Errorf("guess for a non-Cloud host should be bearer")
+	}
+
+	if _, isBasic := c.authenticator(context.Background()).(BasicAuth); !isBasic {
+		t.Errorf("detection after a cancelled request should reach serverInfo")
+	}
+	c.authenticator(context.Background())
+	if calls != 1 {
+		t.Errorf("serverInfo calls = %d, want 1", calls)
+	}
+}
@@ -0,0 +1,59 @@
+package jira
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+type WorklogProblem string
+
+const (
+	ProblemNone         WorklogProblem = ""
+	ProblemNotFound     WorklogProblem = "not_found"
+	ProblemClosed       WorklogProblem = "closed"
+	ProblemNoPermission WorklogProblem = "no_permission"
+)
+
+// WorklogCheck tells whether work can be logged on an issue. Issue is nil
+// when the key does not exist; its Key may differ from the requested one
+// if the issue was moved to another project.
+type WorklogCheck struct {
+	Issue   *Issue
+	Problem WorklogProblem
+}
+
+// CheckWorklogTarget verifies that the issue exists, is not resolved and
+// that the current user has the WORK_ON_ISSUES permission on it.
+func (c *Client) CheckWorklogTarget(ctx context.Context, issueKey string) (WorklogCheck, error) {
+	q := url.Values{}
+	q.Set("fields", searchFields)
+
+	var si searchIssue
+	err := c.doJSON(ctx, http.MethodGet, "/rest/api/2/issue/"+url.PathEscape(issueKey), q, nil, &si)
+	if hasStatus(err, http.StatusNotFound) {
+		return WorklogCheck{Problem: ProblemNotFound}, nil
+	}
+	if err != nil {
+		return WorklogCheck{}, err
+	}
+	issue := si.toIssue()
+
+	q = url.Values{}
+	q.Set("issueKey", issue.Key)
+	q.Set("permissions", "WORK_ON_ISSUES")
+
+	var perms permissionsResponse
+	if err := c.doJSON(ctx, http.MethodGet, "/rest/api/2/mypermissions", q, nil, &perms); err != nil {
+		return WorklogCheck{}, err
+	}
+
+	check := WorklogCheck{Issue: &issue}
+	switch {
+	case !perms.Permissions["WORK_ON_ISSUES"].HavePermission:
+		check.Problem = ProblemNoPermission
+	case issue.StatusCategory == "done":
+		check.Problem = ProblemClosed
+	}
+	return check, nil
+}
@@ -0,0 +1,67 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckWorklogTarget(t *testing.T) {
+	tests := []struct {
+		name        string
+		requested   string
+		key         string // key Jira returns; empty means the issue is missing
+		category    string
+		permission  bool
+		wantProblem WorklogProblem
+		wantKey     string
+	}{
+		{"ok", "PROJ-1", "PROJ-1", "indeterminate", true, ProblemNone, "PROJ-1"},
+		{"not found", "PROJ-404", "", "", false, ProblemNotFound, ""},
+		{"moved", "OLD-7", "NEW-3", "new", true, ProblemNone, "NEW-3"},
+		{"done", "PROJ-2", "PROJ-2", "done", true, ProblemClosed, "PROJ-2"},
+		{"no permission", "PROJ-3", "PROJ-3", "new", false, ProblemNoPermission, "PROJ-3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/rest/api/2/issue/" + tt.requested:
+					if tt.key == "" {
+						w.WriteHeader(http.StatusNotFound)
+						w.Write([]byte(`{"errorMessages":["Issue does not exist or you do not have permission to see it."]}`))
+						return
+					}
+					fmt.Fprintf(w, `{"id":"10","key":%q,"fields":{"summary":"s","status":{"name":"x","statusCategory":{"key":%q}}}}`, tt.key, tt.category)
+				case "/rest/api/2/mypermissions":
+					if got := r.URL.Query().Get("issueKey"); got != tt.key {
+						t.Errorf("permissions asked for %q, want %q", got, tt.key)
+					}
+					fmt.Fprintf(w, `{"permissions":{"WORK_ON_ISSUES":{"havePermission":%t}}}`, tt.permission)
+				default:
+					t.Errorf("unexpected request %s", r.URL.Path)
+					http.NotFound(w, r)
+				}
+			}))
+			defer srv.Close()
+
+			c := NewClient(srv.URL, "", "token", AuthBearer)
+			check, err := c.CheckWorklogTarget(context.Background(), tt.requested)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if check.Problem != tt.wantProblem {
+				t.Errorf("problem = %q, want %q", check.Problem, tt.wantProblem)
+			}
+			switch {
+			case tt.wantKey == "" && check.Issue != nil:
+				t.Errorf("got issue %+v for a missing key", check.Issue)
+			case tt.wantKey != "" && (check.Issue == nil || check.Issue.Key != tt.wantKey):
+				t.Errorf("issue = %+v, want key %s", check.Issue, tt.wantKey)
+			}
+		})
+	}
+}
@@ -9,252 +9,288 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
 type Client struct {
-	baseURL  string
-	apiToken string
-	http     *http.Client
+	baseURL   string
+	email     string
+	apiToken  string
+	authMode  AuthMode
+	http      *http.Client
+	transport *retryTransport
+
+	concurrency int
+	location    *time.Location
+	pool        IssuePool
+	policies    WorklogPolicies
+
+	mu        sync.Mutex
+	me        *myselfResponse
+	tag       WorklogTag
+	epicField *string
+
+	authMu sync.Mutex
+	auth   Authenticator
+
+	legacySearch   atomic.Bool
+	legacyWorklogs atomic.Bool
 }
 
-func NewClient(baseURL, email, apiToken string) *Client {
+func NewClient(baseURL, email, apiToken string, authMode AuthMode) *Client {
+	transport := newRetryTransport(http.DefaultTransport.(*http.Transport).Clone())
 	return &Client{
-		baseURL:  strings.TrimRight(baseURL, "/"),
-		apiToken: apiToken,
-		http:     &http.Client{},
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		email:     email,
+		apiToken:  apiToken,
+		authMode:  authMode,
+		http:      &http.Client{Transport: transport},
+		transport: transport,
+
+		concurrency: DefaultConcurrency,
+		pool:        DefaultIssuePool(),
 	}
 }
 
-func (c *Client) GetMyIssues(ctx context.Context) ([]Issue, error) {
-	jql := `assignee = currentUser() AND status != "Done" AND issuetype not in (Story, Epic) ORDER BY updated DESC`
-	return c.searchIssues(ctx, jql)
+// SetProxy routes the client's requests through proxy; nil connects directly.
+func (c *Client) SetProxy(proxy func(*http.Request) (*url.URL, error)) {
+	c.baseTransport().Proxy = proxy
 }
 
-func (c *Client) GetAllIssues(ctx context.Context) ([]Issue, error) {
-	jql := `status != "Done" AND issuetype not in (Story, Epic) ORDER BY updated DESC`
-	return c.searchIssues(ctx, jql)
+// baseTransport is the client's own http.Transport under the retry layer.
+func (c *Client) baseTransport() *http.Transport {
+	return c.transport.base.(*http.Transport)
 }
 
-func (c *Client) searchIssues(ctx context.Context, jql string) ([]Issue, error) {
-	var issues []Issue
-	startAt := 0
-	const pageSize = 100
-
-	for {
-		u, err := url.Parse(c.baseURL + "/rest/api/2/search")
-		if err != nil {
-			return nil, fmt.Errorf("parse URL: %w", err)
-		}
-
-		q := u.Query()
-		q.Set("jql", jql)
-		q.Set("fields", "summary,status")
-		q.Set("maxResults", fmt.Sprintf("%d", pageSize))
-		q.Set("startAt", fmt.Sprintf("%d", startAt))
-		u.RawQuery = q.Encode()
-
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
-		if err != nil {
-			return nil, fmt.Errorf("create request: %w", err)
-		}
-
-		req.Header.Set("Accept", "application/json")
-		req.Header.Set("Authorization", "Bearer "+c.apiToken)
-
-		resp, err := c.http.Do(req)
-		if err != nil {
-			return nil, fmt.Errorf("jira request: %w", err)
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			return nil, fmt.Errorf("jira returned %d: %s", resp.StatusCode, string(body))
-		}
+func (c *Client) GetMyIssues(ctx context.Context) ([]Issue, error) {
+	jql := "assignee = currentUser()"
+	if pool := c.pool.JQL(); pool != "" {
+		jql += " AND " + pool
+	}
+	return c.searchIssues(ctx, withOrder(jql))
+}
 
-		var sr searchResponse
-		if err := json.NewDecoder(resp.Body).Decode(&sr); err != nil {
-			return nil, fmt.Errorf("decode response: %w", err)
-		}
+func (c *Client) GetAllIssues(ctx context.Context) ([]Issue, error) {
+	return c.searchIssues(ctx, withOrder(c.pool.JQL()))
+}
 
-		for _, si := range sr.Issues {
-			status := ""
-			if si.Fields.Status != nil {
-				status = si.Fields.Status.Name
-			}
-			issues = append(issues, Issue{
-				Key:     si.Key,
-				Summary: si.Fields.Summary,
-				Status:  status,
-			})
-		}
+// LogWork creates a worklog with the project's policy and returns its ID.
+func (c *Client) LogWork(ctx context.Context, issueKey string, timeSpentSeconds int, description string, started time.Time) (string, error) {
+	return c.LogWorkWithVisibility(ctx, issueKey, timeSpentSeconds, description, started, nil)
+}
 
-		startAt += len(sr.Issues)
-		if startAt >= sr.Total {
-			break
-		}
+// LogWorkWithVisibility is LogWork with the visibility overridden. A nil
+// visibility keeps the project's default; an unrestricted one makes the
+// worklog visible to everyone.
+func (c *Client) LogWorkWithVisibility(ctx context.Context, issueKey string, timeSpentSeconds int, description string, started time.Time, visibility *Visibility) (string, error) {
+	policy := c.policies.For(issueKey)
+	if visibility == nil {
+		visibility = policy.Visibility
 	}
 
-	return issues, nil
-}
-
-func (c *Client) LogWork(ctx context.Context, issueKey string, timeSpentSeconds int, description string, started time.Time) error {
 	payload := worklogPayload{
 		TimeSpentSeconds: timeSpentSeconds,
 		Comment:          description,
+		Properties:       c.tagProperties(),
 	}
 	if !started.IsZero() {
-		payload.Started = started.Format("2006-01-02T09:00:00.000+0000")
+		payload.Started = started.Format(jiraTimeLayout)
 	}
-
-	body, err := json.Marshal(payload)
-	if err != nil {
-		return fmt.Errorf("marshal payload: %w", err)
+	if visibility.Restricted() {
+		payload.Visibility = visibility
 	}
 
-	endpoint := c.baseURL + "/rest/api/2/issue/" + url.PathEscape(issueKey) + "/worklog"
+	path := "/rest/api/2/issue/" + url.PathEscape(issueKey) + "/worklog"
+	query := policy.query()
+	var created worklogEntry
+	err := c.doJSON(ctx, http.MethodPost, path, query, payload, &created)
+
+	// The transport does not repeat a POST that may have reached Jira. Retry
+	// here only after making sure the first attempt did not create the worklog.
+	for attempt := 0; err != nil && isTransient(err) && !started.IsZero() && attempt < maxRetries; attempt++ {
+		id, checkErr := c.findWorklog(ctx, issueKey, started, timeSpentSeconds, description)
+		if id != "" {
+			return id, nil
+		}
+		if checkErr != nil {
+			return "", err
+		}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
-	if err != nil {
-		return fmt.Errorf("create request: %w", err)
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(c.transport.jitteredBackoff(attempt)):
+		}
+		err = c.doJSON(ctx, http.MethodPost, path, query, payload, &created)
 	}
-
-	req.Header.Set("Authorization", "Bearer "+c.apiToken)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.http.Do(req)
 	if err != nil {
-		return fmt.Errorf("jira request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		respBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("jira returned %d: %s", resp.StatusCode, string(respBody))
+		return "", err
 	}
-
-	return nil
+	return created.ID, nil
 }
 
-func (c *Client) GetTodayLoggedSeconds(ctx context.Context) (int, error) {
-	accountID, err := c.getMyAccountID(ctx)
+// findWorklog returns the ID of the current user's worklog with exactly these
+// values, or "" if there is none.
+func (c *Client) findWorklog(ctx context.Context, issueKey string, started time.Time, timeSpentSeconds int, comment string) (string, error) {
+	accountID, err := c.AccountID(ctx)
 	if err != nil {
-		return 0, fmt.Errorf("get current user: %w", err)
+		return "", err
 	}
-
-	jql := `worklogDate >= startOfDay() AND worklogAuthor = currentUser()`
-	issues, err := c.searchIssues(ctx, jql)
+	worklogs, err := c.getIssueWorklogs(ctx, issueKey, started.Add(-time.Minute), started.Add(time.Minute))
 	if err != nil {
-		return 0, fmt.Errorf("search worklogs: %w", err)
+		return "", err
 	}
-
-	today := time.Now().Format("2006-01-02")
-	totalSeconds := 0
-
-	for _, issue := range issues {
-		worklogs, err := c.getIssueWorklogs(ctx, issue.Key)
-		if err != nil {
-			continue
-		}
-		for _, wl := range worklogs {
-			if (wl.Author.AccountID == accountID || wl.Author.Name == accountID) && strings.HasPrefix(wl.Started, today) {
-				totalSeconds += wl.TimeSpentSeconds
-			}
+	for _, wl := range worklogs {
+		if wl.AuthorID == accountID && wl.Started.Equal(started) &&
+			wl.TimeSpentSeconds == timeSpentSeconds && wl.Comment == comment {
+			return wl.ID, nil
 		}
 	}
-
-	return totalSeconds, nil
+	return "", nil
 }
 
-func (c *Client) GetLoggedSecondsForDateRange(ctx context.Context, startDate, endDate string) (map[string]int, error) {
-	accountID, err := c.getMyAccountID(ctx)
+// AccountID returns the current user's account ID (Cloud) or user name (Server).
+func (c *Client) AccountID(ctx context.Context) (string, error) {
+	me, err := c.myself(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("get current user: %w", err)
+		return "", err
 	}
 
-	jql := fmt.Sprintf(`worklogDate >= "%s" AND worklogDate <= "%s" AND worklogAuthor = currentUser()`, startDate, endDate)
-	issues, err := c.searchIssues(ctx, jql)
-	if err != nil {
-		return nil, fmt.Errorf("search worklogs: %w", err)
+	if me.AccountID != "" {
+		return me.AccountID, nil
+	}
+	return me.Name, nil
+}
+
+// IssueID resolves an issue key to its numeric ID.
+func (c *Client) IssueID(ctx context.Context, issueKey string) (string, error) {
+	q := url.Values{}
+	q.Set("fields", "summary")
+
+	var si searchIssue
+	if err := c.doJSON(ctx, http.MethodGet, "/rest/api/2/issue/"+url.PathEscape(issueKey), q, nil, &si); err != nil {
+		return "", err
 	}
+	return si.ID, nil
+}
 
-	result := make(map[string]int)
-	for _, issue := range issues {
-		worklogs, err := c.getIssueWorklogs(ctx, issue.Key)
+// IssueKeys maps issue IDs to keys, for APIs such as Tempo that only return IDs.
+// IDs of issues that are not found or not visible are left out.
+func (c *Client) IssueKeys(ctx context.Context, ids []string) (map[string]string, error) {
+	keys := make(map[string]string, len(ids))
+	for start := 0; start < len(ids); start += issueIDBatch {
+		end := min(start+issueIDBatch, len(ids))
+		issues, err := c.searchIssues(ctx, "id in ("+strings.Join(ids[start:end], ",")+")")
+		if hasStatus(err, http.StatusBadRequest) {
+			// JQL rejects the whole query when one of the IDs is deleted or hidden.
+			err = c.issueKeysByID(ctx, ids[start:end], keys)
+		} else if err == nil {
+			for _, issue := range issues {
+				keys[issue.ID] = issue.Key
+			}
+		}
 		if err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
+
+// issueKeysByID looks the issues up one by one, skipping the ones Jira does
+// not show.
+func (c *Client) issueKeysByID(ctx context.Context, ids []string, keys map[string]string) error {
+	q := url.Values{}
+	q.Set("fields", "summary")
+	for _, id := range ids {
+		var si searchIssue
+		err := c.doJSON(ctx, http.MethodGet, "/rest/api/2/issue/"+url.PathEscape(id), q, nil, &si)
+		if IsNotFound(err) || IsForbidden(err) {
 			continue
 		}
-		for _, wl := range worklogs {
-			if wl.Author.AccountID != accountID && wl.Author.Name != accountID {
-				continue
-			}
-			// Extract date from "2006-01-02T15:04:05.000+0000"
-			if len(wl.Started) >= 10 {
-				day := wl.Started[:10]
-				if day >= startDate && day <= endDate {
-					result[day] += wl.TimeSpentSeconds
-				}
-			}
+		if err != nil {
+			return err
 		}
+		keys[id] = si.Key
 	}
-
-	return result, nil
+	return nil
 }
 
-func (c *Client) getMyAccountID(ctx context.Context) (string, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/rest/api/2/myself", nil)
-	if err != nil {
-		return "", err
+// myself returns the current user, cached after the first successful call.
+func (c *Client) myself(ctx context.Context) (*myselfResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.me != nil {
+		return c.me, nil
 	}
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.apiToken)
 
-	resp, err := c.http.Do(req)
-	if err != nil {
-		return "", err
+	var me myselfResponse
+	if err := c.doJSON(ctx, http.MethodGet, "/rest/api/2/myself", nil, nil, &me); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
+	c.me = &me
+	return c.me, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("jira returned %d: %s", resp.StatusCode, string(body))
+// doJSON sends an authenticated request and decodes the JSON response into out (if non-nil).
+func (c *Client) doJSON(ctx context.Context, method, path string, query url.Values, payload, out any) error {
+	req, err := c.newRequest(ctx, method, path, query, payload)
+	if err != nil {
+		return err
 	}
+	if err := c.authenticator(ctx).Authenticate(req); err != nil {
+		return fmt.Errorf("authenticate: %w", err)
+	}
+	return c.do(req, out)
+}
 
-	var me myselfResponse
-	if err := json.NewDecoder(resp.Body).Decode(&me); err != nil {
-		return "", err
+func (c *Client) newRequest(ctx context.Context, method, path string, query url.Values, payload any) (*http.Request, error) {
+	u, err := url.Parse(c.baseURL + path)
+	if err != nil {
+		return nil, fmt.Errorf("parse URL: %w", err)
+	}
+	if len(query) > 0 {
+		u.RawQuery = query.Encode()
 	}
 
-	if me.AccountID != "" {
-		return me.AccountID, nil
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("marshal payload: %w", err)
+		}
+		body = bytes.NewReader(data)
 	}
-	return me.Name, nil
-}
 
-func (c *Client) getIssueWorklogs(ctx context.Context, issueKey string) ([]worklogEntry, error) {
-	endpoint := c.baseURL + "/rest/api/2/issue/" + url.PathEscape(issueKey) + "/worklog"
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("create request: %w", err)
 	}
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.apiToken)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
 
+func (c *Client) do(req *http.Request, out any) error {
 	resp, err := c.http.Do(req)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("jira request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("jira returned %d", resp.StatusCode)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return newAPIError(resp, body)
 	}
 
-	var wlResp worklogListResponse
-	if err := json.NewDecoder(resp.Body).Decode(&wlResp); err != nil {
-		return nil, err
+	if out == nil {
+		return nil
 	}
-
-	return wlResp.Worklogs, nil
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
 }
@@ -0,0 +1,111 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const DefaultConcurrency = 4
+
+// IssueErrors reports issues whose worklogs could not be read. It is returned
+// together with results that are complete for every other issue.
+type IssueErrors map[string]error
+
+func (e IssueErrors) Error() string {
+	keys := e.Keys()
+	return fmt.Sprintf("could not read worklogs of %s: %v", strings.Join(keys, ", "), e[keys[0]])
+}
+
+// Keys returns the failed issue keys in sorted order.
+func (e IssueErrors) Keys() []string {
+	keys := make([]string, 0, len(e))
+	for k := range e {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// isPartial reports an error that leaves the results usable for all but the
+// failed issues.
+func isPartial(err error) bool {
+	return errors.As(err, &IssueErrors{})
+}
+
+// SetConcurrency limits how many issues are read in parallel.
+func (c *Client) SetConcurrency(n int) {
+	if n < 1 {
+		n = DefaultConcurrency
+	}
+	c.concurrency = n
+}
+
+// fetchIssueWorklogs reads worklogs of several issues with at most
+// c.concurrency requests in flight. Per-issue failures are collected into
+// IssueErrors; cancellation of ctx aborts the whole fetch.
+func (c *Client) fetchIssueWorklogs(ctx context.Context, keys []string, from, to time.Time) ([]Worklog, error) {
+	type result struct {
+		key      string
+		worklogs []Worklog
+		err      error
+	}
+
+	jobs := make(chan string)
+	results := make(chan result)
+
+	var wg sync.WaitGroup
+	for range min(c.concurrency, len(keys)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for key := range jobs {
+				worklogs, err := c.getIssueWorklogs(ctx, key, from, to)
+				results <- result{key: key, worklogs: worklogs, err: err}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, key := range keys {
+			select {
+			case jobs <- key:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	byKey := make(map[string][]Worklog, len(keys))
+	failed := IssueErrors{}
+	for r := range results {
+		if r.err != nil {
+			failed[r.key] = r.err
+			continue
+		}
+		byKey[r.key] = r.worklogs
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var worklogs []Worklog
+	for _, key := range keys {
+		worklogs = append(worklogs, byKey[key]...)
+	}
+	if len(failed) > 0 {
+		return worklogs, failed
+	}
+	return worklogs, nil
+}
@@ -0,0 +1,65 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFetchIssueWorklogsAggregatesErrors(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		if strings.Contains(r.URL.Path, "/BAD-") {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(`{"total":1,"worklogs":[{"id":"1","timeSpentSeconds":60,"started":"2025-03-03T10:00:00.000+0000"}]}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", "token", AuthBearer)
+	c.SetConcurrency(2)
+
+	keys := []string{"A-1", "BAD-1", "A-2", "A-3", "BAD-2", "A-4"}
+	worklogs, err := c.fetchIssueWorklogs(context.Background(), keys, time.Time{}, time.Time{})
+
+	var issueErrs IssueErrors
+	if !errors.As(err, &issueErrs) {
+		t.Fatalf("expected IssueErrors, got %v", err)
+	}
+	if got := strings.Join(issueErrs.Keys(), ","); got != "BAD-1,BAD-2" {
+		t.Errorf("failed keys = %s", got)
+	}
+	if len(worklogs) != 4 || worklogs[0].IssueKey != "A-1" || worklogs[3].IssueKey != "A-4" {
+		t.Errorf("unexpected worklogs %+v", worklogs)
+	}
+	if peak.Load() > 2 {
+		t.Errorf("peak concurrency %d, want <= 2", peak.Load())
+	}
+}
+
+func TestIsPartial(t *testing.T) {
+	failed := IssueErrors{"A-1": errors.New("boom")}
+	if !isPartial(failed) || !isPartial(fmt.Errorf("list worklogs: %w", failed)) {
+		t.Error("isPartial() = false for IssueErrors")
+	}
+	if isPartial(errors.New("boom")) {
+		t.Error("isPartial() = true for a plain error")
+	}
+}
@@ -0,0 +1,117 @@
+package jira
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// maxErrorBody limits how much of a non-JSON error body is kept.
+const maxErrorBody = 200
+
+// APIError is a non-2xx response from the Jira REST API.
+type APIError struct {
+	StatusCode int
+	// Messages are the general errorMessages of the response.
+	Messages []string
+	// FieldErrors maps a field name to its validation message.
+	FieldErrors map[string]string
+	RequestID   string
+	// Body holds the start of a non-JSON, non-HTML response body.
+	Body string
+}
+
+func (e *APIError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "jira returned %d", e.StatusCode)
+	if details := e.Details(); details != "" {
+		sb.WriteString(": " + details)
+	}
+	if e.RequestID != "" {
+		fmt.Fprintf(&sb, " (request ID %s)", e.RequestID)
+	}
+	return sb.String()
+}
+
+// Details joins the error messages and field errors into one line.
+func (e *APIError) Details() string {
+	parts := append([]string(nil), e.Messages...)
+
+	fields := make([]string, 0, len(e.FieldErrors))
+	for f := range e.FieldErrors {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	for _, f := range fields {
+		parts = append(parts, f+": "+e.FieldErrors[f])
+	}
+
+	if len(parts) == 0 {
+		return e.Body
+	}
+	return strings.Join(parts, "; ")
+}
+
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get("X-Arequestid"),
+	}
+
+	var parsed struct {
+		ErrorMessages []string          `json:"errorMessages"`
+		Errors        map[string]string `json:"errors"`
+	}
+	// Gateways and other Atlassian APIs answer with {"message": ...} and
+	// similar shapes; those are kept as the raw body below.
+	if json.Unmarshal(body, &parsed) == nil && len(parsed.ErrorMessages)+len(parsed.Errors) > 0 {
+		apiErr.Messages = parsed.ErrorMessages
+		apiErr.FieldErrors = parsed.Errors
+		return apiErr
+	}
+
+	// Proxies and gateways answer with HTML pages that are of no use in a terminal.
+	text := strings.TrimSpace(string(body))
+	if !strings.HasPrefix(text, "<") {
+		if len(text) > maxErrorBody {
+			text = text[:maxErrorBody] + "..."
+		}
+		apiErr.Body = text
+	}
+	return apiErr
+}
+
+func statusOf(err error) int {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+func hasStatus(err error, code int) bool {
+	return statusOf(err) == code
+}
+
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized)
+}
+
+func IsForbidden(err error) bool {
+	return hasStatus(err, http.StatusForbidden)
+}
+
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+func IsRateLimited(err error) bool {
+	return hasStatus(err, http.StatusTooManyRequests)
+}
+
+func IsServerError(err error) bool {
+	return statusOf(err) >= 500
+}
@@ -0,0 +1,76 @@
+package jira
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    string
+		checkFn func(error) bool
+	}{
+		{
+			"json messages",
+			http.StatusBadRequest,
+			`{"errorMessages":["Issue does not exist"],"errors":{"timeLogged":"must be positive"}}`,
+			"jira returned 400: Issue does not exist; timeLogged: must be positive (request ID req-1)",
+			func(error) bool { return true },
+		},
+		{
+			"json without jira fields",
+			http.StatusForbidden,
+			`{"message":"Client must be authenticated to access this resource."}`,
+			`jira returned 403: {"message":"Client must be authenticated to access this resource."} (request ID req-1)`,
+			IsForbidden,
+		},
+		{
+			"html from proxy",
+			http.StatusBadGateway,
+			`<html><body><h1>502 Bad Gateway</h1></body></html>`,
+			"jira returned 502 (request ID req-1)",
+			IsServerError,
+		},
+		{
+			"unauthorized",
+			http.StatusUnauthorized,
+			`Unauthorized`,
+			"jira returned 401: Unauthorized (request ID req-1)",
+			IsUnauthorized,
+		},
+		{
+			"rate limited",
+			http.StatusTooManyRequests,
+			`{"errorMessages":["Rate limit exceeded"]}`,
+			"jira returned 429: Rate limit exceeded (request ID req-1)",
+			IsRateLimited,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-AREQUESTID", "req-1")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			_, err := newTestClient(srv.URL).AccountID(context.Background())
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if err.Error() != tt.want {
+				t.Errorf("Error() = %q, want %q", err.Error(), tt.want)
+			}
+			if !tt.checkFn(err) {
+				t.Errorf("classification helper returned false for %v", err)
+			}
+		})
+	}
+}
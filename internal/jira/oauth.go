@@ -0,0 +1,320 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultOAuthCallbackPort is the loopback port of the redirect URI
+// registered for the app in the Atlassian developer console.
+const DefaultOAuthCallbackPort = 53682
+
+const (
+	oauthScopes = "read:jira-work write:jira-work read:jira-user offline_access"
+	// refreshMargin renews the access token a bit before it actually expires.
+	refreshMargin = time.Minute
+	// oauthTimeout bounds token and site requests, which are not retried.
+	oauthTimeout = 30 * time.Second
+)
+
+// Atlassian OAuth 2.0 endpoints. They are variables so that tests can point
+// them at a local server.
+var (
+	oauthAuthorizeURL = "https://auth.atlassian.com/authorize"
+	oauthTokenURL     = "https://auth.atlassian.com/oauth/token"
+	oauthResourcesURL = "https://api.atlassian.com/oauth/token/accessible-resources"
+	oauthAPIURL       = "https://api.atlassian.com/ex/jira/"
+)
+
+// OAuthConfig identifies the OAuth 2.0 (3LO) app registered for sj.
+// CallbackPort must match the app's redirect URI; 0 picks a free port.
+// Transport carries the token requests; nil means http.DefaultTransport.
+type OAuthConfig struct {
+	ClientID     string
+	ClientSecret string
+	CallbackPort int
+	Transport    http.RoundTripper
+}
+
+func (cfg OAuthConfig) httpClient() *http.Client {
+	return &http.Client{Transport: cfg.Transport, Timeout: oauthTimeout}
+}
+
+// OAuthToken is a 3LO grant for one Jira Cloud site.
+type OAuthToken struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	CloudID      string    `json:"cloud_id"`
+	SiteURL      string    `json:"site_url"`
+}
+
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+	Error        string `json:"error"`
+	Description  string `json:"error_description"`
+}
+
+type accessibleResource struct {
+	ID   string `json:"id"`
+	URL  string `json:"url"`
+	Name string `json:"name"`
+}
+
+// Login runs the authorization code flow with PKCE: it starts a loopback
+// server for the redirect, hands the consent URL to openURL, exchanges the
+// returned code for tokens and resolves the cloud ID of siteURL.
+func Login(ctx context.Context, cfg OAuthConfig, siteURL string, openURL func(string)) (*OAuthToken, error) {
+	client := cfg.httpClient()
+
+	ln, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", cfg.CallbackPort))
+	if err != nil {
+		return nil, fmt.Errorf("start callback server: %w", err)
+	}
+	redirectURI := fmt.Sprintf("http://127.0.0.1:%d/callback", ln.Addr().(*net.TCPAddr).Port)
+
+	verifier := randomString(32)
+	challenge := sha256.Sum256([]byte(verifier))
+	state := randomString(16)
+
+	type callback struct {
+		code string
+		err  error
+	}
+	done := make(chan callback, 1)
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/callback" {
+			http.NotFound(w, r)
+			return
+		}
+		q := r.URL.Query()
+		var cb callback
+		switch {
+		case q.Get("state") != state:
+			cb.err = errors.New("OAuth state mismatch")
+		case q.Get("error") != "":
+			cb.err = fmt.Errorf("authorization denied: %s %s", q.Get("error"), q.Get("error_description"))
+		default:
+			cb.code = q.Get("code")
+		}
+		if cb.err != nil {
+			http.Error(w, cb.err.Error(), http.StatusBadRequest)
+		} else {
+			io.WriteString(w, "sj: вход выполнен, окно можно закрыть.")
+		}
+		select {
+		case done <- cb:
+		default:
+		}
+	})}
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	q := url.Values{}
+	q.Set("audience", "api.atlassian.com")
+	q.Set("client_id", cfg.ClientID)
+	q.Set("scope", oauthScopes)
+	q.Set("redirect_uri", redirectURI)
+	q.Set("state", state)
+	q.Set("response_type", "code")
+	q.Set("prompt", "consent")
+	q.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	q.Set("code_challenge_method", "S256")
+	openURL(oauthAuthorizeURL + "?" + q.Encode())
+
+	var cb callback
+	select {
+	case cb = <-done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if cb.err != nil {
+		return nil, cb.err
+	}
+
+	token, err := requestToken(ctx, client, map[string]string{
+		"grant_type":    "authorization_code",
+		"client_id":     cfg.ClientID,
+		"client_secret": cfg.ClientSecret,
+		"code":          cb.code,
+		"redirect_uri":  redirectURI,
+		"code_verifier": verifier,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	site, err := findSite(ctx, client, token.AccessToken, siteURL)
+	if err != nil {
+		return nil, err
+	}
+	token.CloudID = site.ID
+	token.SiteURL = site.URL
+	return token, nil
+}
+
+// findSite picks the Jira site the grant gives access to by its URL.
+func findSite(ctx context.Context, client *http.Client, accessToken, siteURL string) (*accessibleResource, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, oauthResourcesURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("list accessible sites: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, newAPIError(resp, body)
+	}
+
+	var sites []accessibleResource
+	if err := json.NewDecoder(resp.Body).Decode(&sites); err != nil {
+		return nil, fmt.Errorf("decode accessible sites: %w", err)
+	}
+
+	want := strings.TrimRight(siteURL, "/")
+	var names []string
+	for i, s := range sites {
+		if strings.EqualFold(strings.TrimRight(s.URL, "/"), want) {
+			return &sites[i], nil
+		}
+		names = append(names, s.URL)
+	}
+	return nil, fmt.Errorf("site %s is not among the sites granted to sj: %s", siteURL, strings.Join(names, ", "))
+}
+
+func requestToken(ctx context.Context, client *http.Client, params map[string]string) (*OAuthToken, error) {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, oauthTokenURL, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var tr tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tr); err != nil {
+		return nil, fmt.Errorf("decode token response (HTTP %d): %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK || tr.AccessToken == "" {
+		return nil, fmt.Errorf("token request failed (HTTP %d): %s %s", resp.StatusCode, tr.Error, tr.Description)
+	}
+	return &OAuthToken{
+		AccessToken:  tr.AccessToken,
+		RefreshToken: tr.RefreshToken,
+		ExpiresAt:    time.Now().Add(time.Duration(tr.ExpiresIn) * time.Second),
+	}, nil
+}
+
+func randomString(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// OAuthAuth signs requests with an OAuth access token and refreshes it
+// shortly before it expires. Every refreshed token is passed to save, since
+// Atlassian rotates refresh tokens.
+type OAuthAuth struct {
+	config OAuthConfig
+	save   func(OAuthToken) error
+
+	mu    sync.Mutex
+	token OAuthToken
+}
+
+func NewOAuthAuth(cfg OAuthConfig, token OAuthToken, save func(OAuthToken) error) *OAuthAuth {
+	return &OAuthAuth{config: cfg, token: token, save: save}
+}
+
+func (a *OAuthAuth) Authenticate(req *http.Request) error {
+	token, err := a.accessToken(req.Context())
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+func (a *OAuthAuth) accessToken(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if time.Until(a.token.ExpiresAt) > refreshMargin {
+		return a.token.AccessToken, nil
+	}
+	if a.token.RefreshToken == "" {
+		return "", errors.New("OAuth token expired, run sj login")
+	}
+
+	fresh, err := requestToken(ctx, a.config.httpClient(), map[string]string{
+		"grant_type":    "refresh_token",
+		"client_id":     a.config.ClientID,
+		"client_secret": a.config.ClientSecret,
+		"refresh_token": a.token.RefreshToken,
+	})
+	if err != nil {
+		return "", fmt.Errorf("refresh OAuth token (run sj login): %w", err)
+	}
+	if fresh.RefreshToken == "" {
+		fresh.RefreshToken = a.token.RefreshToken
+	}
+	fresh.CloudID = a.token.CloudID
+	fresh.SiteURL = a.token.SiteURL
+	a.token = *fresh
+
+	if a.save != nil {
+		if err := a.save(a.token); err != nil {
+			return "", fmt.Errorf("save refreshed OAuth token: %w", err)
+		}
+	}
+	return a.token.AccessToken, nil
+}
+
+// Login runs Login for the client's site over the client's transport, so
+// that the token requests use its proxy and TLS settings.
+func (c *Client) Login(ctx context.Context, cfg OAuthConfig, openURL func(string)) (*OAuthToken, error) {
+	cfg.Transport = c.baseTransport()
+	return Login(ctx, cfg, c.baseURL, openURL)
+}
+
+// UseOAuth signs requests with the OAuth token and routes them through the
+// Atlassian API gateway of the token's site, which 3LO tokens require.
+// Token refreshes go over the client's transport.
+func (c *Client) UseOAuth(auth *OAuthAuth) {
+	auth.config.Transport = c.baseTransport()
+	c.baseURL = oauthAPIURL + auth.token.CloudID
+	// The authenticator is known, skip auto-detection.
+	c.authMu.Lock()
+	c.auth = auth
+	c.authMu.Unlock()
+}
@@ -0,0 +1,126 @@
+package jira
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// fakeAtlassian serves the token and accessible-resources endpoints and
+// checks the PKCE verifier against the challenge sent to /authorize.
+func fakeAtlassian(t *testing.T, challenge *string) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/oauth/token":
+			var params map[string]string
+			json.NewDecoder(r.Body).Decode(&params)
+			switch params["grant_type"] {
+			case "authorization_code":
+				sum := sha256.Sum256([]byte(params["code_verifier"]))
+				if params["code"] != "abc" || base64.RawURLEncoding.EncodeToString(sum[:]) != *challenge {
+					w.WriteHeader(http.StatusBadRequest)
+					w.Write([]byte(`{"error":"invalid_grant"}`))
+					return
+				}
+				w.Write([]byte(`{"access_token":"at1","refresh_token":"rt1","expires_in":3600}`))
+			case "refresh_token":
+				if params["refresh_token"] != "rt1" {
+					t.Errorf("refresh with %q", params["refresh_token"])
+				}
+				w.Write([]byte(`{"access_token":"at2","refresh_token":"rt2","expires_in":3600}`))
+			}
+		case "/oauth/token/accessible-resources":
+			if r.Header.Get("Authorization") != "Bearer at1" {
+				t.Errorf("resources requested with %q", r.Header.Get("Authorization"))
+			}
+			w.Write([]byte(`[{"id":"other","url":"https://other.atlassian.net"},{"id":"cloud-1","url":"https://acme.atlassian.net"}]`))
+		case "/ex/jira/cloud-1/rest/api/2/myself":
+			w.Write([]byte(`{"accountId":"me","auth":"` + r.Header.Get("Authorization") + `"}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+
+	oldToken, oldResources, oldAPI := oauthTokenURL, oauthResourcesURL, oauthAPIURL
+	oauthTokenURL = srv.URL + "/oauth/token"
+	oauthResourcesURL = srv.URL + "/oauth/token/accessible-resources"
+	oauthAPIURL = srv.URL + "/ex/jira/"
+	t.Cleanup(func() {
+		oauthTokenURL, oauthResourcesURL, oauthAPIURL = oldToken, oldResources, oldAPI
+		srv.Close()
+	})
+	return srv
+}
+
+func TestLogin(t *testing.T) {
+	var challenge string
+	fakeAtlassian(t, &challenge)
+
+	// The "browser" approves the consent screen and follows the redirect.
+	openURL := func(authURL string) {
+		u, _ := url.Parse(authURL)
+		q := u.Query()
+		if q.Get("code_challenge_method") != "S256" || q.Get("client_id") != "cid" {
+			t.Errorf("unexpected authorize URL %s", authURL)
+		}
+		challenge = q.Get("code_challenge")
+		go func() {
+			resp, err := http.Get(q.Get("redirect_uri") + "?code=abc&state=" + url.QueryEscape(q.Get("state")))
+			if err == nil {
+				resp.Body.Close()
+			}
+		}()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	token, err := Login(ctx, OAuthConfig{ClientID: "cid"}, "https://acme.atlassian.net/", openURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.AccessToken != "at1" || token.RefreshToken != "rt1" || token.CloudID != "cloud-1" {
+		t.Errorf("got %+v", token)
+	}
+}
+
+func TestOAuthRoutesAndRefreshes(t *testing.T) {
+	var challenge string
+	fakeAtlassian(t, &challenge)
+
+	var saved OAuthToken
+	auth := NewOAuthAuth(OAuthConfig{ClientID: "cid"},
+		OAuthToken{AccessToken: "at1", RefreshToken: "rt1", ExpiresAt: time.Now(), CloudID: "cloud-1"},
+		func(tok OAuthToken) error { saved = tok; return nil })
+
+	c := NewClient("https://acme.atlassian.net", "", "", AuthAuto)
+	// Record what goes through the client's transport, e.g. to a proxy.
+	var proxied []string
+	c.SetProxy(func(r *http.Request) (*url.URL, error) {
+		proxied = append(proxied, r.URL.Path)
+		return nil, nil
+	})
+	c.UseOAuth(auth)
+
+	var me struct {
+		Auth string `json:"auth"`
+	}
+	if err := c.doJSON(context.Background(), http.MethodGet, "/rest/api/2/myself", nil, nil, &me); err != nil {
+		t.Fatal(err)
+	}
+	if me.Auth != "Bearer at2" {
+		t.Errorf("request sent with %q, want the refreshed token", me.Auth)
+	}
+	if saved.RefreshToken != "rt2" || saved.CloudID != "cloud-1" {
+		t.Errorf("saved %+v", saved)
+	}
+	if len(proxied) == 0 || proxied[0] != "/oauth/token" {
+		t.Errorf("requests through the client's transport: %v, want the token refresh first", proxied)
+	}
+}
@@ -0,0 +1,190 @@
+package jira
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// EstimateAdjust is how Jira updates the remaining estimate when work is logged.
+type EstimateAdjust string
+
+const (
+	// EstimateAuto reduces the remaining estimate by the time spent (Jira's default).
+	EstimateAuto EstimateAdjust = "auto"
+	// EstimateLeave keeps the remaining estimate unchanged.
+	EstimateLeave EstimateAdjust = "leave"
+	// EstimateNew sets the remaining estimate to NewEstimate.
+	EstimateNew EstimateAdjust = "new"
+	// EstimateManual reduces the remaining estimate by ReduceBy.
+	EstimateManual EstimateAdjust = "manual"
+)
+
+const (
+	VisibilityGroup = "group"
+	VisibilityRole  = "role"
+)
+
+// Visibility restricts a worklog to members of a group or project role.
+// An empty Type means visible to everyone.
+type Visibility struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// Restricted reports whether the worklog is hidden from some users.
+func (v *Visibility) Restricted() bool {
+	return v != nil && v.Type != ""
+}
+
+func (v *Visibility) validate() error {
+	if !v.Restricted() {
+		return nil
+	}
+	if v.Type != VisibilityGroup && v.Type != VisibilityRole {
+		return fmt.Errorf("unknown visibility type %q, want group or role", v.Type)
+	}
+	if v.Value == "" {
+		return fmt.Errorf("visibility %s requires value", v.Type)
+	}
+	return nil
+}
+
+// WorklogPolicy controls how worklogs are created. NewEstimate and ReduceBy
+// use Jira duration syntax, e.g. "2d 4h".
+type WorklogPolicy struct {
+	AdjustEstimate EstimateAdjust `json:"adjust_estimate,omitempty"`
+	NewEstimate    string         `json:"new_estimate,omitempty"`
+	ReduceBy       string         `json:"reduce_by,omitempty"`
+	Visibility     *Visibility    `json:"visibility,omitempty"`
+}
+
+// ProjectPolicy overrides the default policy for a single project.
+// Empty fields inherit the defaults.
+type ProjectPolicy struct {
+	Key string `json:"key"`
+	WorklogPolicy
+}
+
+// WorklogPolicies is the default worklog policy with per-project overrides.
+type WorklogPolicies struct {
+	WorklogPolicy
+	Projects []ProjectPolicy `json:"projects,omitempty"`
+}
+
+// SetWorklogPolicies sets the policies applied by LogWork, UpdateWorklog and
+// DeleteWorklog.
+func (c *Client) SetWorklogPolicies(policies WorklogPolicies) {
+	c.policies = policies
+}
+
+// WorklogPolicy returns the policy applied to worklogs on the issue.
+func (c *Client) WorklogPolicy(issueKey string) WorklogPolicy {
+	return c.policies.For(issueKey)
+}
+
+// For returns the policy for the project of the issue.
+func (p WorklogPolicies) For(issueKey string) WorklogPolicy {
+	project := projectKey(issueKey)
+	for _, pp := range p.Projects {
+		if strings.EqualFold(pp.Key, project) {
+			return p.WorklogPolicy.merge(pp.WorklogPolicy)
+		}
+	}
+	return p.WorklogPolicy
+}
+
+// Validate checks that every policy is complete.
+func (p WorklogPolicies) Validate() error {
+	if err := p.WorklogPolicy.validate(); err != nil {
+		return err
+	}
+	for _, pp := range p.Projects {
+		if pp.Key == "" {
+			return fmt.Errorf("project policy without key")
+		}
+		if err := pp.WorklogPolicy.validate(); err != nil {
+			return fmt.Errorf("project %s: %w", pp.Key, err)
+		}
+	}
+	return nil
+}
+
+func (p WorklogPolicy) merge(override WorklogPolicy) WorklogPolicy {
+	// The estimate fields only make sense together.
+	if override.AdjustEstimate != "" {
+		p.AdjustEstimate = override.AdjustEstimate
+		p.NewEstimate = override.NewEstimate
+		p.ReduceBy = override.ReduceBy
+	}
+	if override.Visibility != nil {
+		p.Visibility = override.Visibility
+	}
+	return p
+}
+
+func (p WorklogPolicy) validate() error {
+	if err := p.Visibility.validate(); err != nil {
+		return err
+	}
+	switch p.AdjustEstimate {
+	case "", EstimateAuto, EstimateLeave:
+	case EstimateNew:
+		if p.NewEstimate == "" {
+			return fmt.Errorf("adjust_estimate %q requires new_estimate", p.AdjustEstimate)
+		}
+	case EstimateManual:
+		if p.ReduceBy == "" {
+			return fmt.Errorf("adjust_estimate %q requires reduce_by", p.AdjustEstimate)
+		}
+	default:
+		return fmt.Errorf("unknown adjust_estimate %q", p.AdjustEstimate)
+	}
+	return nil
+}
+
+// query renders the estimate policy as worklog POST parameters.
+func (p WorklogPolicy) query() url.Values {
+	if p.AdjustEstimate == "" {
+		return nil
+	}
+	q := url.Values{}
+	q.Set("adjustEstimate", string(p.AdjustEstimate))
+	switch p.AdjustEstimate {
+	case EstimateNew:
+		q.Set("newEstimate", p.NewEstimate)
+	case EstimateManual:
+		q.Set("reduceBy", p.ReduceBy)
+	}
+	return q
+}
+
+// updateQuery renders the estimate policy as worklog PUT parameters. PUT has
+// no counterpart of reduceBy, so manual leaves the estimate alone rather than
+// letting Jira adjust it automatically.
+func (p WorklogPolicy) updateQuery() url.Values {
+	if p.AdjustEstimate == EstimateManual {
+		return url.Values{"adjustEstimate": {string(EstimateLeave)}}
+	}
+	return p.query()
+}
+
+// deleteQuery renders the estimate policy as worklog DELETE parameters.
+// Manual gives back what was taken when the worklog was created.
+func (p WorklogPolicy) deleteQuery() url.Values {
+	if p.AdjustEstimate != EstimateManual {
+		return p.query()
+	}
+	q := url.Values{}
+	q.Set("adjustEstimate", string(EstimateManual))
+	q.Set("increaseBy", p.ReduceBy)
+	return q
+}
+
+// projectKey returns the project part of an issue key ("PROJ" for "PROJ-12").
+func projectKey(issueKey string) string {
+	if i := strings.LastIndex(issueKey, "-"); i > 0 {
+		return strings.ToUpper(issueKey[:i])
+	}
+	return strings.ToUpper(issueKey)
+}
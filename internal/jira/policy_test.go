@@ -0,0 +1,164 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWorklogPoliciesFor(t *testing.T) {
+	policies := WorklogPolicies{
+		WorklogPolicy: WorklogPolicy{AdjustEstimate: EstimateLeave},
+		Projects: []ProjectPolicy{
+			{Key: "OPS", WorklogPolicy: WorklogPolicy{AdjustEstimate: EstimateNew, NewEstimate: "1d"}},
+			{Key: "DOCS"},
+		},
+	}
+
+	tests := []struct {
+		key  string
+		want WorklogPolicy
+	}{
+		{"PROJ-1", WorklogPolicy{AdjustEstimate: EstimateLeave}},
+		{"ops-7", WorklogPolicy{AdjustEstimate: EstimateNew, NewEstimate: "1d"}},
+		{"DOCS-2", WorklogPolicy{AdjustEstimate: EstimateLeave}},
+	}
+	for _, tt := range tests {
+		if got := policies.For(tt.key); got != tt.want {
+			t.Errorf("For(%q) = %+v, want %+v", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestWorklogPoliciesValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  WorklogPolicy
+		wantErr bool
+	}{
+		{"default", WorklogPolicy{}, false},
+		{"leave", WorklogPolicy{AdjustEstimate: EstimateLeave}, false},
+		{"new without value", WorklogPolicy{AdjustEstimate: EstimateNew}, true},
+		{"manual", WorklogPolicy{AdjustEstimate: EstimateManual, ReduceBy: "30m"}, false},
+		{"manual without value", WorklogPolicy{AdjustEstimate: EstimateManual}, true},
+		{"unknown", WorklogPolicy{AdjustEstimate: "shrink"}, true},
+		{"role", WorklogPolicy{Visibility: &Visibility{Type: VisibilityRole, Value: "Developers"}}, false},
+		{"role without name", WorklogPolicy{Visibility: &Visibility{Type: VisibilityRole}}, true},
+		{"unknown visibility", WorklogPolicy{Visibility: &Visibility{Type: "user", Value: "bob"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := WorklogPolicies{Projects: []ProjectPolicy{{Key: "P", WorklogPolicy: tt.policy}}}.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLogWorkAdjustsEstimate(t *testing.T) {
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Write([]byte(`{"id":"1"}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", "token", AuthBearer)
+	c.SetWorklogPolicies(WorklogPolicies{Projects: []ProjectPolicy{
+		{Key: "PROJ", WorklogPolicy: WorklogPolicy{AdjustEstimate: EstimateManual, ReduceBy: "30m"}},
+	}})
+	if _, err := c.LogWork(context.Background(), "PROJ-1", 3600, "review", time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	if query != "adjustEstimate=manual&reduceBy=30m" {
+		t.Errorf("got query %q", query)
+	}
+}
+
+func TestUpdateAndDeleteWorklogAdjustEstimate(t *testing.T) {
+	var method, query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, query = r.Method, r.URL.RawQuery
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Write([]byte(`{"id":"7"}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", "token", AuthBearer)
+	c.SetWorklogPolicies(WorklogPolicies{
+		WorklogPolicy: WorklogPolicy{AdjustEstimate: EstimateLeave},
+		Projects: []ProjectPolicy{
+			{Key: "MAN", WorklogPolicy: WorklogPolicy{AdjustEstimate: EstimateManual, ReduceBy: "30m"}},
+			{Key: "NEW", WorklogPolicy: WorklogPolicy{AdjustEstimate: EstimateNew, NewEstimate: "2d"}},
+		},
+	})
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		call   func() error
+		method string
+		want   string
+	}{
+		{"update leave", func() error { return c.UpdateWorklog(ctx, "PROJ-1", "7", 60, "") }, http.MethodPut, "adjustEstimate=leave"},
+		{"update manual", func() error { return c.UpdateWorklog(ctx, "MAN-1", "7", 60, "") }, http.MethodPut, "adjustEstimate=leave"},
+		{"update new", func() error { return c.UpdateWorklog(ctx, "NEW-1", "7", 60, "") }, http.MethodPut, "adjustEstimate=new&newEstimate=2d"},
+		{"delete leave", func() error { return c.DeleteWorklog(ctx, "PROJ-1", "7") }, http.MethodDelete, "adjustEstimate=leave"},
+		{"delete manual", func() error { return c.DeleteWorklog(ctx, "MAN-1", "7") }, http.MethodDelete, "adjustEstimate=manual&increaseBy=30m"},
+		{"delete new", func() error { return c.DeleteWorklog(ctx, "NEW-1", "7") }, http.MethodDelete, "adjustEstimate=new&newEstimate=2d"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); err != nil {
+				t.Fatal(err)
+			}
+			if method != tt.method || query != tt.want {
+				t.Errorf("got %s ?%s, want %s ?%s", method, query, tt.method, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogWorkVisibility(t *testing.T) {
+	var got worklogPayload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = worklogPayload{}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"id":"1"}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", "token", AuthBearer)
+	c.SetWorklogPolicies(WorklogPolicies{Projects: []ProjectPolicy{
+		{Key: "SEC", WorklogPolicy: WorklogPolicy{Visibility: &Visibility{Type: VisibilityRole, Value: "Security"}}},
+	}})
+	ctx := context.Background()
+
+	if _, err := c.LogWork(ctx, "SEC-1", 60, "", time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	if got.Visibility == nil || *got.Visibility != (Visibility{Type: VisibilityRole, Value: "Security"}) {
+		t.Errorf("project default not applied: %+v", got.Visibility)
+	}
+
+	if _, err := c.LogWorkWithVisibility(ctx, "SEC-1", 60, "", time.Time{}, &Visibility{}); err != nil {
+		t.Fatal(err)
+	}
+	if got.Visibility != nil {
+		t.Errorf("override to everyone not applied: %+v", got.Visibility)
+	}
+
+	if _, err := c.LogWork(ctx, "PROJ-1", 60, "", time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	if got.Visibility != nil {
+		t.Errorf("unexpected visibility for PROJ: %+v", got.Visibility)
+	}
+}
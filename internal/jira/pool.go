@@ -0,0 +1,218 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidJQL is returned by ValidateJQL when Jira rejects the query.
+var ErrInvalidJQL = errors.New("invalid JQL")
+
+// PoolFilter narrows down which issues are offered to the assistant.
+type PoolFilter struct {
+	ExcludeStatusCategories []string `json:"exclude_status_categories,omitempty"`
+	IssueTypes              []string `json:"issue_types,omitempty"`
+	ExcludeIssueTypes       []string `json:"exclude_issue_types,omitempty"`
+	ExtraJQL                string   `json:"extra_jql,omitempty"`
+}
+
+// ProjectPool overrides the default filter for a single project.
+// Empty fields inherit the defaults.
+type ProjectPool struct {
+	Key string `json:"key"`
+	PoolFilter
+}
+
+// Built-in issue pool profiles.
+const (
+	// ProfileTasks offers unresolved task-level issues.
+	ProfileTasks = "tasks"
+	// ProfileAll offers unresolved issues of every type.
+	ProfileAll = "all"
+	// ProfileInProgress offers only issues that are being worked on.
+	ProfileInProgress = "in-progress"
+	// ProfileCustom has no defaults of its own: the pool's fields are used as is.
+	ProfileCustom = "custom"
+)
+
+// builtinProfiles use status categories instead of status names, which may
+// be localized.
+var builtinProfiles = map[string]PoolFilter{
+	ProfileTasks: {
+		ExcludeStatusCategories: []string{"Done"},
+		ExcludeIssueTypes:       []string{"Story", "Epic"},
+	},
+	ProfileAll: {
+		ExcludeStatusCategories: []string{"Done"},
+	},
+	ProfileInProgress: {
+		ExcludeStatusCategories: []string{"To Do", "Done"},
+		ExcludeIssueTypes:       []string{"Story", "Epic"},
+	},
+	ProfileCustom: {},
+}
+
+// IssuePool is the set of issues the user can log work on. Its filter is
+// layered: the defaults of the chosen profile, then the pool's own fields,
+// then per-project overrides. Without projects it spans the whole site.
+type IssuePool struct {
+	// Profile names a built-in profile or one from Profiles; empty means custom.
+	Profile  string                `json:"profile,omitempty"`
+	Profiles map[string]PoolFilter `json:"profiles,omitempty"`
+	PoolFilter
+	Projects []ProjectPool `json:"projects,omitempty"`
+}
+
+// DefaultIssuePool matches every unresolved task-level issue.
+func DefaultIssuePool() IssuePool {
+	return IssuePool{Profile: ProfileTasks}
+}
+
+// ProfileNames lists the built-in profiles followed by the pool's own, sorted.
+// A profile of the pool with a built-in name replaces the built-in one.
+func (p IssuePool) ProfileNames() []string {
+	names := []string{ProfileTasks, ProfileAll, ProfileInProgress, ProfileCustom}
+	var custom []string
+	for name := range p.Profiles {
+		if _, ok := builtinProfiles[name]; !ok {
+			custom = append(custom, name)
+		}
+	}
+	sort.Strings(custom)
+	return append(names, custom...)
+}
+
+// ProfileDefaults returns the filter of the named profile.
+func (p IssuePool) ProfileDefaults(name string) (PoolFilter, bool) {
+	if f, ok := p.Profiles[name]; ok {
+		return f, true
+	}
+	if name == "" {
+		name = ProfileCustom
+	}
+	f, ok := builtinProfiles[name]
+	return f, ok
+}
+
+// Validate reports a profile that is neither built in nor defined in Profiles.
+func (p IssuePool) Validate() error {
+	if _, ok := p.ProfileDefaults(p.Profile); !ok {
+		return fmt.Errorf("unknown profile %q", p.Profile)
+	}
+	for _, project := range p.Projects {
+		if project.Key == "" {
+			return fmt.Errorf("project pool without key")
+		}
+	}
+	return nil
+}
+
+// defaults is the filter for projects without overrides.
+func (p IssuePool) defaults() PoolFilter {
+	base, _ := p.ProfileDefaults(p.Profile)
+	return base.merge(p.PoolFilter)
+}
+
+// SetIssuePool replaces the pool used by GetMyIssues and GetAllIssues.
+func (c *Client) SetIssuePool(pool IssuePool) {
+	c.pool = pool
+}
+
+// JQL renders the pool as a JQL condition without ORDER BY.
+func (p IssuePool) JQL() string {
+	defaults := p.defaults()
+	if len(p.Projects) == 0 {
+		return strings.Join(defaults.clauses(), " AND ")
+	}
+
+	var projects []string
+	for _, project := range p.Projects {
+		clauses := append([]string{"project = " + quoteJQL(project.Key)}, defaults.merge(project.PoolFilter).clauses()...)
+		projects = append(projects, "("+strings.Join(clauses, " AND ")+")")
+	}
+	if len(projects) == 1 {
+		return projects[0]
+	}
+	return "(" + strings.Join(projects, " OR ") + ")"
+}
+
+func (f PoolFilter) merge(override PoolFilter) PoolFilter {
+	if len(override.ExcludeStatusCategories) > 0 {
+		f.ExcludeStatusCategories = override.ExcludeStatusCategories
+	}
+	if len(override.IssueTypes) > 0 {
+		f.IssueTypes = override.IssueTypes
+	}
+	if len(override.ExcludeIssueTypes) > 0 {
+		f.ExcludeIssueTypes = override.ExcludeIssueTypes
+	}
+	if override.ExtraJQL != "" {
+		f.ExtraJQL = override.ExtraJQL
+	}
+	return f
+}
+
+func (f PoolFilter) clauses() []string {
+	var clauses []string
+	if len(f.ExcludeStatusCategories) > 0 {
+		clauses = append(clauses, "statusCategory not in "+quoteJQLList(f.ExcludeStatusCategories))
+	}
+	if len(f.IssueTypes) > 0 {
+		clauses = append(clauses, "issuetype in "+quoteJQLList(f.IssueTypes))
+	}
+	if len(f.ExcludeIssueTypes) > 0 {
+		clauses = append(clauses, "issuetype not in "+quoteJQLList(f.ExcludeIssueTypes))
+	}
+	if extra := strings.TrimSpace(f.ExtraJQL); extra != "" {
+		clauses = append(clauses, "("+extra+")")
+	}
+	return clauses
+}
+
+// ValidateJQL asks Jira to run the query for a single result, so syntax
+// errors and unknown projects, types or fields are reported up front.
+func (c *Client) ValidateJQL(ctx context.Context, jql string) error {
+	q := url.Values{}
+	q.Set("jql", jql)
+	q.Set("fields", "summary")
+	q.Set("maxResults", "1")
+
+	var err error
+	if !c.legacySearch.Load() {
+		err = c.doJSON(ctx, http.MethodGet, enhancedSearchAPI, q, nil, nil)
+		if hasStatus(err, http.StatusNotFound) || hasStatus(err, http.StatusMethodNotAllowed) {
+			c.legacySearch.Store(true)
+		}
+	}
+	if c.legacySearch.Load() {
+		err = c.doJSON(ctx, http.MethodGet, legacySearchAPI, q, nil, nil)
+	}
+	if hasStatus(err, http.StatusBadRequest) {
+		return fmt.Errorf("%w: %v", ErrInvalidJQL, err)
+	}
+	return err
+}
+
+func withOrder(condition string) string {
+	if condition == "" {
+		return "ORDER BY updated DESC"
+	}
+	return condition + " ORDER BY updated DESC"
+}
+
+func quoteJQL(s string) string {
+	return `"` + strings.ReplaceAll(strings.TrimSpace(s), `"`, `\"`) + `"`
+}
+
+func quoteJQLList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = quoteJQL(v)
+	}
+	return fmt.Sprintf("(%s)", strings.Join(quoted, ", "))
+}
@@ -0,0 +1,102 @@
+package jira
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIssuePoolJQL(t *testing.T) {
+	tests := []struct {
+		name string
+		pool IssuePool
+		want string
+	}{
+		{
+			"default",
+			DefaultIssuePool(),
+			`statusCategory not in ("Done") AND issuetype not in ("Story", "Epic")`,
+		},
+		{
+			"empty",
+			IssuePool{},
+			``,
+		},
+		{
+			"single project inherits defaults",
+			IssuePool{
+				PoolFilter: PoolFilter{ExcludeStatusCategories: []string{"Done"}},
+				Projects:   []ProjectPool{{Key: "PROJ"}},
+			},
+			`(project = "PROJ" AND statusCategory not in ("Done"))`,
+		},
+		{
+			"all profile",
+			IssuePool{Profile: ProfileAll},
+			`statusCategory not in ("Done")`,
+		},
+		{
+			"in-progress profile",
+			IssuePool{Profile: ProfileInProgress},
+			`statusCategory not in ("To Do", "Done") AND issuetype not in ("Story", "Epic")`,
+		},
+		{
+			"pool fields over profile, project over both",
+			IssuePool{
+				Profile:    ProfileTasks,
+				PoolFilter: PoolFilter{ExcludeIssueTypes: []string{"Epic"}},
+				Projects: []ProjectPool{
+					{Key: "A"},
+					{Key: "B", PoolFilter: PoolFilter{ExcludeStatusCategories: []string{"To Do", "Done"}}},
+				},
+			},
+			`((project = "A" AND statusCategory not in ("Done") AND issuetype not in ("Epic")) OR ` +
+				`(project = "B" AND statusCategory not in ("To Do", "Done") AND issuetype not in ("Epic")))`,
+		},
+		{
+			"profile from config",
+			IssuePool{
+				Profile:  "support",
+				Profiles: map[string]PoolFilter{"support": {IssueTypes: []string{"Incident"}}},
+			},
+			`issuetype in ("Incident")`,
+		},
+		{
+			"per-project overrides",
+			IssuePool{
+				PoolFilter: PoolFilter{ExcludeStatusCategories: []string{"Done"}, ExtraJQL: "labels = work"},
+				Projects: []ProjectPool{
+					{Key: "A"},
+					{Key: "B", PoolFilter: PoolFilter{IssueTypes: []string{"Bug"}, ExtraJQL: `component = "Back end"`}},
+				},
+			},
+			`((project = "A" AND statusCategory not in ("Done") AND (labels = work)) OR ` +
+				`(project = "B" AND statusCategory not in ("Done") AND issuetype in ("Bug") AND (component = "Back end")))`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.pool.JQL(); got != tt.want {
+				t.Errorf("JQL() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIssuePoolValidate(t *testing.T) {
+	if err := DefaultIssuePool().Validate(); err != nil {
+		t.Errorf("default pool: %v", err)
+	}
+	if err := (IssuePool{}).Validate(); err != nil {
+		t.Errorf("pool without profile: %v", err)
+	}
+	if err := (IssuePool{Profile: "support"}).Validate(); err == nil {
+		t.Errorf("unknown profile accepted")
+	}
+
+	pool := IssuePool{Profiles: map[string]PoolFilter{"support": {}, "all": {}}}
+	want := []string{ProfileTasks, ProfileAll, ProfileInProgress, ProfileCustom, "support"}
+	if got := pool.ProfileNames(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("ProfileNames() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,95 @@
+package jira
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestClient(url string) *Client {
+	c := NewClient(url, "", "token", AuthBearer)
+	c.transport.backoff = time.Millisecond
+	return c
+}
+
+func TestRetryTransportRetriesGet(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch calls.Add(1) {
+		case 1:
+			w.WriteHeader(http.StatusBadGateway)
+		case 2:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.Write([]byte(`{"accountId":"me"}`))
+		}
+	}))
+	defer srv.Close()
+
+	id, err := newTestClient(srv.URL).AccountID(context.Background())
+	if err != nil || id != "me" {
+		t.Fatalf("AccountID() = %q, %v", id, err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("server called %d times, want 3", calls.Load())
+	}
+}
+
+func TestLogWorkDoesNotDuplicate(t *testing.T) {
+	var posts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/rest/api/2/myself":
+			w.Write([]byte(`{"accountId":"me"}`))
+		case r.Method == http.MethodPost:
+			posts.Add(1)
+			// Jira stored the worklog, but the proxy timed out on the response.
+			w.WriteHeader(http.StatusGatewayTimeout)
+		case r.Method == http.MethodGet:
+			w.Write([]byte(`{"total":1,"worklogs":[{"id":"9","author":{"accountId":"me"},
+				"comment":"review","timeSpentSeconds":3600,"started":"2025-03-04T09:00:00.000+0300"}]}`))
+		}
+	}))
+	defer srv.Close()
+
+	started := time.Date(2025, 3, 4, 9, 0, 0, 0, time.FixedZone("MSK", 3*3600))
+	id, err := newTestClient(srv.URL).LogWork(context.Background(), "PROJ-1", 3600, "review", started)
+	if err != nil || id != "9" {
+		t.Fatalf("LogWork() = %q, %v", id, err)
+	}
+	if posts.Load() != 1 {
+		t.Errorf("worklog posted %d times, want 1", posts.Load())
+	}
+}
+
+func TestLogWorkRetriesWhenNotCreated(t *testing.T) {
+	var posts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/rest/api/2/myself":
+			w.Write([]byte(`{"accountId":"me"}`))
+		case r.Method == http.MethodPost:
+			if posts.Add(1) == 1 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":"10"}`))
+		case r.Method == http.MethodGet:
+			w.Write([]byte(`{"total":0,"worklogs":[]}`))
+		}
+	}))
+	defer srv.Close()
+
+	started := time.Date(2025, 3, 4, 9, 0, 0, 0, time.UTC)
+	if id, err := newTestClient(srv.URL).LogWork(context.Background(), "PROJ-1", 3600, "review", started); err != nil || id != "10" {
+		t.Fatalf("LogWork() = %q, %v", id, err)
+	}
+	if posts.Load() != 2 {
+		t.Errorf("worklog posted %d times, want 2", posts.Load())
+	}
+}
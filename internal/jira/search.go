@@ -0,0 +1,164 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	searchPageSize    = 100
+	enhancedSearchAPI = "/rest/api/3/search/jql"
+	legacySearchAPI   = "/rest/api/2/search"
+
+	searchFields = "summary,status,project,issuetype,parent,labels,components,assignee,timetracking"
+)
+
+// searchIssues runs a JQL query and returns every matching issue.
+// Jira Cloud is served by the enhanced endpoint (nextPageToken paging);
+// instances without it (Server / Data Center) fall back to startAt/total paging.
+func (c *Client) searchIssues(ctx context.Context, jql string) ([]Issue, error) {
+	if !c.legacySearch.Load() {
+		issues, err := c.searchEnhanced(ctx, jql)
+		if !hasStatus(err, http.StatusNotFound) && !hasStatus(err, http.StatusMethodNotAllowed) {
+			return issues, err
+		}
+		c.legacySearch.Store(true)
+	}
+	return c.searchLegacy(ctx, jql)
+}
+
+func (c *Client) searchEnhanced(ctx context.Context, jql string) ([]Issue, error) {
+	var issues []Issue
+	pageToken := ""
+
+	for {
+		q := url.Values{}
+		q.Set("jql", jql)
+		q.Set("fields", searchFields)
+		q.Set("maxResults", fmt.Sprintf("%d", searchPageSize))
+		if pageToken != "" {
+			q.Set("nextPageToken", pageToken)
+		}
+
+		var sr enhancedSearchResponse
+		if err := c.doJSON(ctx, http.MethodGet, enhancedSearchAPI, q, nil, &sr); err != nil {
+			return nil, err
+		}
+
+		for _, si := range sr.Issues {
+			issues = append(issues, si.toIssue())
+		}
+
+		if sr.IsLast || sr.NextPageToken == "" || len(sr.Issues) == 0 {
+			break
+		}
+		pageToken = sr.NextPageToken
+	}
+
+	return issues, nil
+}
+
+func (c *Client) searchLegacy(ctx context.Context, jql string) ([]Issue, error) {
+	var issues []Issue
+	startAt := 0
+
+	epicField := c.epicLinkField(ctx)
+	fields := searchFields
+	if epicField != "" {
+		fields += "," + epicField
+	}
+
+	for {
+		q := url.Values{}
+		q.Set("jql", jql)
+		q.Set("fields", fields)
+		q.Set("maxResults", fmt.Sprintf("%d", searchPageSize))
+		q.Set("startAt", fmt.Sprintf("%d", startAt))
+
+		var sr searchResponse
+		if err := c.doJSON(ctx, http.MethodGet, legacySearchAPI, q, nil, &sr); err != nil {
+			return nil, err
+		}
+
+		for _, si := range sr.Issues {
+			issue := si.toIssue()
+			if issue.ParentKey == "" && epicField != "" {
+				issue.ParentKey = si.Fields.customString(epicField)
+			}
+			issues = append(issues, issue)
+		}
+
+		startAt += len(sr.Issues)
+		if len(sr.Issues) == 0 || startAt >= sr.Total {
+			break
+		}
+	}
+
+	c.fillEpicSummaries(ctx, issues)
+	return issues, nil
+}
+
+// epicLinkSchema is the custom field type of Jira Software's Epic Link. On
+// Server / Data Center epics are linked through it rather than through parent.
+const epicLinkSchema = "com.pyxis.greenhopper.jira:gh-epic-link"
+
+// epicLinkField returns the ID of the Epic Link field, or "" if the instance
+// has none or the fields cannot be read. Only a successful lookup is cached.
+func (c *Client) epicLinkField(ctx context.Context) string {
+	c.mu.Lock()
+	cached := c.epicField
+	c.mu.Unlock()
+	if cached != nil {
+		return *cached
+	}
+
+	var fields []fieldInfo
+	if err := c.doJSON(ctx, http.MethodGet, "/rest/api/2/field", nil, nil, &fields); err != nil {
+		return ""
+	}
+	id := ""
+	for _, f := range fields {
+		if f.Schema.Custom == epicLinkSchema {
+			id = f.ID
+			break
+		}
+	}
+
+	c.mu.Lock()
+	c.epicField = &id
+	c.mu.Unlock()
+	return id
+}
+
+// fillEpicSummaries sets ParentSummary of issues linked to an epic through
+// Epic Link, which only carries the epic's key. Failures leave it empty.
+func (c *Client) fillEpicSummaries(ctx context.Context, issues []Issue) {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, issue := range issues {
+		if issue.ParentKey != "" && issue.ParentSummary == "" && !seen[issue.ParentKey] {
+			seen[issue.ParentKey] = true
+			keys = append(keys, issue.ParentKey)
+		}
+	}
+	if len(keys) == 0 {
+		return
+	}
+
+	epics, err := c.searchIssues(ctx, "key in ("+strings.Join(keys, ",")+")")
+	if err != nil {
+		return
+	}
+	summaries := make(map[string]string, len(epics))
+	for _, epic := range epics {
+		summaries[epic.Key] = epic.Summary
+	}
+	for i := range issues {
+		if issues[i].ParentSummary == "" {
+			issues[i].ParentSummary = summaries[issues[i].ParentKey]
+		}
+	}
+}
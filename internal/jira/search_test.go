@@ -0,0 +1,140 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSearchIssuesEnhanced(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != enhancedSearchAPI {
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+		switch r.URL.Query().Get("nextPageToken") {
+		case "":
+			json.NewEncoder(w).Encode(map[string]any{
+				"issues":        []map[string]any{{"key": "A-1"}, {"key": "A-2"}},
+				"nextPageToken": "p2",
+			})
+		case "p2":
+			json.NewEncoder(w).Encode(map[string]any{
+				"issues": []map[string]any{{"key": "A-3"}},
+				"isLast": true,
+			})
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", "token", AuthBearer)
+	issues, err := c.searchIssues(context.Background(), "project = A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 3 || issues[2].Key != "A-3" {
+		t.Errorf("got %+v", issues)
+	}
+}
+
+func TestSearchIssuesLegacyFallback(t *testing.T) {
+	enhancedCalls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case enhancedSearchAPI:
+			enhancedCalls++
+			http.NotFound(w, r)
+		case legacySearchAPI:
+			if r.URL.Query().Get("startAt") == "0" {
+				json.NewEncoder(w).Encode(map[string]any{
+					"issues": []map[string]any{{"key": "B-1"}, {"key": "B-2"}},
+					"total":  3,
+				})
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]any{
+				"issues":  []map[string]any{{"key": "B-3"}},
+				"startAt": 2,
+				"total":   3,
+			})
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", "token", AuthBearer)
+	for range 2 {
+		issues, err := c.searchIssues(context.Background(), "project = B")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(issues) != 3 {
+			t.Errorf("got %d issues, want 3", len(issues))
+		}
+	}
+	if enhancedCalls != 1 {
+		t.Errorf("enhanced endpoint called %d times, want 1", enhancedCalls)
+	}
+}
+
+func TestSearchIssuesReadsEpicLink(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case enhancedSearchAPI:
+			http.NotFound(w, r)
+		case "/rest/api/2/field":
+			w.Write([]byte(`[{"id":"summary","schema":{"type":"string"}},
+				{"id":"customfield_10008","schema":{"type":"any","custom":"com.pyxis.greenhopper.jira:gh-epic-link"}}]`))
+		case legacySearchAPI:
+			q := r.URL.Query()
+			if !strings.Contains(q.Get("fields"), "customfield_10008") {
+				t.Errorf("Epic Link not requested: %s", q.Get("fields"))
+			}
+			if q.Get("jql") == "key in (B-100)" {
+				w.Write([]byte(`{"total":1,"issues":[{"key":"B-100","fields":{"summary":"Reports"}}]}`))
+				return
+			}
+			w.Write([]byte(`{"total":1,"issues":[{"key":"B-1","fields":{"summary":"Export","customfield_10008":"B-100"}}]}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", "token", AuthBearer)
+	issues, err := c.searchIssues(context.Background(), "project = B")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 || issues[0].ParentKey != "B-100" || issues[0].ParentSummary != "Reports" {
+		t.Errorf("got %+v", issues)
+	}
+}
+
+func TestIssueKeysSkipsMissingIssues(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/3/search/jql":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"errorMessages":["An issue with key '20' does not exist for field 'id'."]}`))
+		case "/rest/api/2/issue/10":
+			w.Write([]byte(`{"id":"10","key":"A-1"}`))
+		case "/rest/api/2/issue/20":
+			http.NotFound(w, r)
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", "token", AuthBearer)
+	keys, err := c.IssueKeys(context.Background(), []string{"10", "20"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || keys["10"] != "A-1" {
+		t.Errorf("got %v", keys)
+	}
+}
@@ -0,0 +1,82 @@
+package jira
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// WorklogTagKey is the worklog property that marks worklogs created by sj.
+const WorklogTagKey = "sj.session"
+
+// WorklogTag is stored in the sj.session property of every worklog created
+// through the client, so that such worklogs can be found later for undo and audit.
+// Tagging is Jira-only: worklogs created through Tempo carry no tag, since
+// Tempo work attributes have to be set up by an admin first, and are undone
+// from the journal alone.
+type WorklogTag struct {
+	Session string `json:"session"`
+	Model   string `json:"model,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
+// NewSessionID returns a unique, roughly time-ordered session identifier.
+func NewSessionID() string {
+	b := make([]byte, 4)
+	rand.Read(b)
+	return time.Now().UTC().Format("20060102T150405") + "-" + hex.EncodeToString(b)
+}
+
+// SetWorklogTag sets the tag attached to worklogs created by LogWork.
+// An empty Session disables tagging.
+func (c *Client) SetWorklogTag(tag WorklogTag) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tag = tag
+}
+
+// WorklogTag returns the tag attached to new worklogs.
+func (c *Client) WorklogTag() WorklogTag {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.tag
+}
+
+// ListTaggedWorklogs returns the current user's worklogs in the period that
+// were created by sj. Like ListMyWorklogs it may return IssueErrors with
+// partial results.
+func (c *Client) ListTaggedWorklogs(ctx context.Context, startDate, endDate string) ([]Worklog, error) {
+	worklogs, err := c.ListMyWorklogs(ctx, startDate, endDate)
+	var tagged []Worklog
+	for _, wl := range worklogs {
+		if wl.Tag != nil {
+			tagged = append(tagged, wl)
+		}
+	}
+	return tagged, err
+}
+
+func (c *Client) tagProperties() []entityProperty {
+	tag := c.WorklogTag()
+	if tag.Session == "" {
+		return nil
+	}
+	return []entityProperty{{Key: WorklogTagKey, Value: tag}}
+}
+
+// parseTag extracts the sj tag from worklog properties, if present.
+func parseTag(props []rawEntityProperty) *WorklogTag {
+	for _, p := range props {
+		if p.Key != WorklogTagKey {
+			continue
+		}
+		var tag WorklogTag
+		if err := json.Unmarshal(p.Value, &tag); err != nil || tag.Session == "" {
+			return nil
+		}
+		return &tag
+	}
+	return nil
+}
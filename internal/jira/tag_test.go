@@ -0,0 +1,68 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLogWorkAttachesTag(t *testing.T) {
+	var got struct {
+		Properties []rawEntityProperty `json:"properties"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", "token", AuthBearer)
+	c.SetWorklogTag(WorklogTag{Session: "s1", Model: "gemini", Version: "1.0"})
+	if _, err := c.LogWork(context.Background(), "A-1", 60, "c", time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+
+	tag := parseTag(got.Properties)
+	if tag == nil || *tag != (WorklogTag{Session: "s1", Model: "gemini", Version: "1.0"}) {
+		t.Errorf("got properties %+v", got.Properties)
+	}
+}
+
+func TestListTaggedWorklogs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/2/myself":
+			w.Write([]byte(`{"accountId":"me","timeZone":"UTC"}`))
+		case "/rest/api/2/worklog/updated":
+			w.Write([]byte(`{"values":[{"worklogId":1},{"worklogId":2}],"lastPage":true}`))
+		case "/rest/api/2/worklog/list":
+			if r.URL.Query().Get("expand") != "properties" {
+				t.Errorf("properties not expanded")
+			}
+			w.Write([]byte(`[
+				{"id":"1","issueId":"10","author":{"accountId":"me"},"timeSpentSeconds":3600,"started":"2025-03-03T10:00:00.000+0000",
+				 "properties":[{"key":"sj.session","value":{"session":"s1","model":"m"}}]},
+				{"id":"2","issueId":"10","author":{"accountId":"me"},"timeSpentSeconds":1800,"started":"2025-03-03T11:00:00.000+0000"}
+			]`))
+		case "/rest/api/3/search/jql":
+			w.Write([]byte(`{"issues":[{"id":"10","key":"A-1","fields":{}}],"isLast":true}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", "token", AuthBearer)
+	got, err := c.ListTaggedWorklogs(context.Background(), "2025-03-03", "2025-03-03")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].ID != "1" || got[0].IssueKey != "A-1" || got[0].Tag.Session != "s1" {
+		t.Errorf("got %+v", got)
+	}
+}
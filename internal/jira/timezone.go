@@ -0,0 +1,47 @@
+package jira
+
+import (
+	"context"
+	"time"
+)
+
+// workdayStartHour is the local hour at which worklogs for a past day are placed.
+const workdayStartHour = 9
+
+// SetLocation overrides the user's time zone instead of reading it from Jira.
+func (c *Client) SetLocation(loc *time.Location) {
+	c.location = loc
+}
+
+// Location returns the user's time zone: the configured override, then the
+// zone of the Jira profile, then the local zone of this machine.
+func (c *Client) Location(ctx context.Context) *time.Location {
+	if c.location != nil {
+		return c.location
+	}
+	if me, err := c.myself(ctx); err == nil && me.TimeZone != "" {
+		if loc, err := time.LoadLocation(me.TimeZone); err == nil {
+			return loc
+		}
+	}
+	return time.Local
+}
+
+// Today returns the current date in the user's time zone.
+func (c *Client) Today(ctx context.Context) string {
+	return time.Now().In(c.Location(ctx)).Format("2006-01-02")
+}
+
+// WorkdayStart returns the start of the working day for date (YYYY-MM-DD) in
+// the user's time zone. An empty date means today.
+func (c *Client) WorkdayStart(ctx context.Context, date string) (time.Time, error) {
+	loc := c.Location(ctx)
+	if date == "" {
+		date = time.Now().In(loc).Format("2006-01-02")
+	}
+	day, err := time.ParseInLocation("2006-01-02", date, loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return day.Add(workdayStartHour * time.Hour), nil
+}
@@ -0,0 +1,63 @@
+package jira
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSetTLSTrustsCABundle(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"version":"9.12.0","deploymentType":"Server"}`))
+	}))
+	defer srv.Close()
+
+	_, err := newTestClient(srv.URL).ServerInfo(context.Background())
+	if !IsTLSError(err) {
+		t.Fatalf("without CA: err = %v, want a TLS error", err)
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(caFile, ca, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c := newTestClient(srv.URL)
+	if err := c.SetTLS(TLSConfig{CAFile: caFile}); err != nil {
+		t.Fatalf("SetTLS() error: %v", err)
+	}
+	info, err := c.ServerInfo(context.Background())
+	if err != nil || info.Version != "9.12.0" {
+		t.Fatalf("ServerInfo() = %+v, %v", info, err)
+	}
+}
+
+func TestTLSConfigBuildErrors(t *testing.T) {
+	dir := t.TempDir()
+	junk := filepath.Join(dir, "junk.pem")
+	if err := os.WriteFile(junk, []byte("not a certificate"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		cfg  TLSConfig
+	}{
+		{"missing CA file", TLSConfig{CAFile: filepath.Join(dir, "missing.pem")}},
+		{"CA without certificates", TLSConfig{CAFile: junk}},
+		{"cert without key", TLSConfig{CertFile: junk}},
+		{"invalid key pair", TLSConfig{CertFile: junk, KeyFile: junk}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.cfg.Build(); err == nil {
+				t.Error("Build() error = nil")
+			}
+		})
+	}
+}
@@ -1,9 +1,32 @@
 package jira
 
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Issue is a Jira issue with the context the assistant needs to match work
+// to it. StatusCategory is the category key ("new", "indeterminate", "done");
+// ParentKey and ParentSummary describe the epic or parent task, if any.
 type Issue struct {
-	Key     string
-	Summary string
-	Status  string
+	ID             string
+	Key            string
+	Summary        string
+	Status         string
+	StatusCategory string
+	Project        string
+	IssueType      string
+	ParentKey      string
+	ParentSummary  string
+	Labels         []string
+	Components     []string
+	Assignee       string
+
+	OriginalEstimateSeconds  int
+	RemainingEstimateSeconds int
+	TimeSpentSeconds         int
 }
 
 type searchResponse struct {
@@ -13,42 +36,242 @@ type searchResponse struct {
 	Issues     []searchIssue `json:"issues"`
 }
 
+type enhancedSearchResponse struct {
+	Issues        []searchIssue `json:"issues"`
+	NextPageToken string        `json:"nextPageToken"`
+	IsLast        bool          `json:"isLast"`
+}
+
 type searchIssue struct {
+	ID     string      `json:"id"`
 	Key    string      `json:"key"`
 	Fields issueFields `json:"fields"`
 }
 
+func (si searchIssue) toIssue() Issue {
+	f := si.Fields
+	issue := Issue{
+		ID:      si.ID,
+		Key:     si.Key,
+		Summary: f.Summary,
+		Labels:  f.Labels,
+	}
+	if f.Status != nil {
+		issue.Status = f.Status.Name
+		issue.StatusCategory = f.Status.StatusCategory.Key
+	}
+	if f.Project != nil {
+		issue.Project = f.Project.Key
+	}
+	if f.IssueType != nil {
+		issue.IssueType = f.IssueType.Name
+	}
+	if f.Parent != nil {
+		issue.ParentKey = f.Parent.Key
+		issue.ParentSummary = f.Parent.Fields.Summary
+	}
+	for _, c := range f.Components {
+		issue.Components = append(issue.Components, c.Name)
+	}
+	if f.Assignee != nil {
+		issue.Assignee = f.Assignee.DisplayName
+	}
+	if tt := f.TimeTracking; tt != nil {
+		issue.OriginalEstimateSeconds = tt.OriginalEstimateSeconds
+		issue.RemainingEstimateSeconds = tt.RemainingEstimateSeconds
+		issue.TimeSpentSeconds = tt.TimeSpentSeconds
+	}
+	return issue
+}
+
 type issueFields struct {
-	Summary string       `json:"summary"`
-	Status  *issueStatus `json:"status"`
+	Summary      string        `json:"summary"`
+	Status       *issueStatus  `json:"status"`
+	Project      *issueProject `json:"project"`
+	IssueType    *namedField   `json:"issuetype"`
+	Parent       *issueParent  `json:"parent"`
+	Labels       []string      `json:"labels"`
+	Components   []namedField  `json:"components"`
+	Assignee     *issueUser    `json:"assignee"`
+	TimeTracking *timeTracking `json:"timetracking"`
+
+	// custom holds every field by ID, for custom fields such as Epic Link
+	// whose IDs differ between instances.
+	custom map[string]json.RawMessage
+}
+
+func (f *issueFields) UnmarshalJSON(data []byte) error {
+	type plain issueFields
+	if err := json.Unmarshal(data, (*plain)(f)); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &f.custom)
+}
+
+// customString returns a custom field holding a plain string, or "".
+func (f issueFields) customString(id string) string {
+	var s string
+	if raw, ok := f.custom[id]; ok {
+		json.Unmarshal(raw, &s)
+	}
+	return s
 }
 
 type issueStatus struct {
+	Name           string `json:"name"`
+	StatusCategory struct {
+		Key string `json:"key"`
+	} `json:"statusCategory"`
+}
+
+type namedField struct {
+	Name string `json:"name"`
+}
+
+type issueProject struct {
+	Key  string `json:"key"`
 	Name string `json:"name"`
 }
 
+type issueParent struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary string `json:"summary"`
+	} `json:"fields"`
+}
+
+type fieldInfo struct {
+	ID     string `json:"id"`
+	Schema struct {
+		Custom string `json:"custom"`
+	} `json:"schema"`
+}
+
+type issueUser struct {
+	DisplayName string `json:"displayName"`
+}
+
+type timeTracking struct {
+	OriginalEstimateSeconds  int `json:"originalEstimateSeconds"`
+	RemainingEstimateSeconds int `json:"remainingEstimateSeconds"`
+	TimeSpentSeconds         int `json:"timeSpentSeconds"`
+}
+
+type permissionsResponse struct {
+	Permissions map[string]struct {
+		HavePermission bool `json:"havePermission"`
+	} `json:"permissions"`
+}
+
 type worklogPayload struct {
-	TimeSpentSeconds int    `json:"timeSpentSeconds"`
-	Comment          string `json:"comment"`
-	Started          string `json:"started,omitempty"`
+	TimeSpentSeconds int              `json:"timeSpentSeconds"`
+	Comment          string           `json:"comment"`
+	Started          string           `json:"started,omitempty"`
+	Visibility       *Visibility      `json:"visibility,omitempty"`
+	Properties       []entityProperty `json:"properties,omitempty"`
+}
+
+type entityProperty struct {
+	Key   string `json:"key"`
+	Value any    `json:"value"`
+}
+
+type rawEntityProperty struct {
+	Key   string          `json:"key"`
+	Value json.RawMessage `json:"value"`
 }
 
 type myselfResponse struct {
 	AccountID string `json:"accountId"`
 	Name      string `json:"name"`
+	TimeZone  string `json:"timeZone"`
+}
+
+// jiraTimeLayout is the timestamp format used by the Jira REST API v2.
+const jiraTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+// Worklog is a single worklog entry regardless of which endpoint returned it.
+type Worklog struct {
+	ID               string
+	IssueID          string
+	IssueKey         string
+	AuthorID         string
+	Comment          string
+	Started          time.Time
+	TimeSpentSeconds int
+	Tag              *WorklogTag
+}
+
+// Date returns the day the worklog belongs to in the zone of Started.
+func (w Worklog) Date() string {
+	return w.Started.Format("2006-01-02")
 }
 
 type worklogListResponse struct {
-	Worklogs []worklogEntry `json:"worklogs"`
+	StartAt    int            `json:"startAt"`
+	MaxResults int            `json:"maxResults"`
+	Total      int            `json:"total"`
+	Worklogs   []worklogEntry `json:"worklogs"`
 }
 
 type worklogEntry struct {
-	Author           worklogAuthor `json:"author"`
-	TimeSpentSeconds int           `json:"timeSpentSeconds"`
-	Started          string        `json:"started"`
+	ID               string              `json:"id"`
+	IssueID          string              `json:"issueId"`
+	Author           worklogAuthor       `json:"author"`
+	Comment          string              `json:"comment"`
+	TimeSpentSeconds int                 `json:"timeSpentSeconds"`
+	Started          string              `json:"started"`
+	Properties       []rawEntityProperty `json:"properties"`
+}
+
+func (e worklogEntry) toWorklog(issueKey string) (Worklog, error) {
+	started, err := time.Parse(jiraTimeLayout, e.Started)
+	if err != nil {
+		return Worklog{}, fmt.Errorf("parse worklog %s start: %w", e.ID, err)
+	}
+	authorID := e.Author.AccountID
+	if authorID == "" {
+		authorID = e.Author.Name
+	}
+	return Worklog{
+		ID:               e.ID,
+		IssueID:          e.IssueID,
+		IssueKey:         issueKey,
+		AuthorID:         authorID,
+		Comment:          e.Comment,
+		Started:          started,
+		TimeSpentSeconds: e.TimeSpentSeconds,
+		Tag:              parseTag(e.Properties),
+	}, nil
+}
+
+type worklogListRequest struct {
+	IDs []int64 `json:"ids"`
+}
+
+type updatedWorklogsResponse struct {
+	Values   []updatedWorklog `json:"values"`
+	Since    int64            `json:"since"`
+	Until    int64            `json:"until"`
+	LastPage bool             `json:"lastPage"`
+}
+
+type updatedWorklog struct {
+	WorklogID   int64 `json:"worklogId"`
+	UpdatedTime int64 `json:"updatedTime"`
 }
 
 type worklogAuthor struct {
 	AccountID string `json:"accountId"`
 	Name      string `json:"name"`
 }
+
+type ServerInfo struct {
+	BaseURL        string `json:"baseUrl"`
+	Version        string `json:"version"`
+	DeploymentType string `json:"deploymentType"`
+}
+
+func (s *ServerInfo) IsCloud() bool {
+	return strings.EqualFold(s.DeploymentType, "Cloud")
+}
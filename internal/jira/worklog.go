@@ -0,0 +1,308 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// WorkdaySeconds is the length of a working day when no schedule is available.
+const WorkdaySeconds = 8 * 3600
+
+const (
+	// worklogListBatch is the maximum number of IDs accepted by /worklog/list.
+	worklogListBatch = 1000
+	worklogPageSize  = 1000
+	issueIDBatch     = 100
+	// maxUpdatedPages bounds the site-wide /worklog/updated scan. A busier
+	// site is read per issue instead, which only touches the user's issues.
+	maxUpdatedPages = 10
+)
+
+// expandProperties asks Jira to include worklog properties, where sj keeps its tag.
+var expandProperties = url.Values{"expand": {"properties"}}
+
+// errTooManyUpdates stops the bulk read when the site logged more worklogs
+// since the start of the period than maxUpdatedPages covers.
+var errTooManyUpdates = errors.New("too many updated worklogs")
+
+// GetLoggedSecondsForDateRange sums the current user's logged time per day
+// of the user's time zone.
+// If some issues could not be read, the sums for the rest are returned along
+// with an IssueErrors error.
+func (c *Client) GetLoggedSecondsForDateRange(ctx context.Context, startDate, endDate string) (map[string]int, error) {
+	worklogs, err := c.ListMyWorklogs(ctx, startDate, endDate)
+	if err != nil && !isPartial(err) {
+		return nil, err
+	}
+
+	result := make(map[string]int)
+	for _, wl := range worklogs {
+		result[wl.Date()] += wl.TimeSpentSeconds
+	}
+
+	return result, err
+}
+
+// GetRequiredSecondsForDateRange returns the expected working time per day.
+// Plain Jira has no work schedule, so every weekday is a full workday.
+func (c *Client) GetRequiredSecondsForDateRange(ctx context.Context, startDate, endDate string) (map[string]int, error) {
+	start, err := time.Parse("2006-01-02", startDate)
+	if err != nil {
+		return nil, fmt.Errorf("parse start date: %w", err)
+	}
+	end, err := time.Parse("2006-01-02", endDate)
+	if err != nil {
+		return nil, fmt.Errorf("parse end date: %w", err)
+	}
+
+	result := make(map[string]int)
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		if wd := d.Weekday(); wd == time.Saturday || wd == time.Sunday {
+			result[d.Format("2006-01-02")] = 0
+			continue
+		}
+		result[d.Format("2006-01-02")] = WorkdaySeconds
+	}
+	return result, nil
+}
+
+// ListMyWorklogs returns the current user's worklogs started between startDate
+// and endDate inclusive, with issue keys resolved. Start times are converted
+// to the user's time zone so that Worklog.Date matches the dates asked for.
+// Like GetLoggedSecondsForDateRange it may return IssueErrors with partial results.
+func (c *Client) ListMyWorklogs(ctx context.Context, startDate, endDate string) ([]Worklog, error) {
+	accountID, err := c.AccountID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get current user: %w", err)
+	}
+
+	loc := c.Location(ctx)
+	from, err := time.ParseInLocation("2006-01-02", startDate, loc)
+	if err != nil {
+		return nil, fmt.Errorf("parse start date: %w", err)
+	}
+	to, err := time.ParseInLocation("2006-01-02", endDate, loc)
+	if err != nil {
+		return nil, fmt.Errorf("parse end date: %w", err)
+	}
+	to = to.AddDate(0, 0, 1)
+
+	// A partial failure still yields usable worklogs; it is passed on to the caller.
+	worklogs, fetchErr := c.worklogsForPeriod(ctx, from, to)
+	if fetchErr != nil && !isPartial(fetchErr) {
+		return nil, fetchErr
+	}
+
+	var mine []Worklog
+	for _, wl := range worklogs {
+		if wl.AuthorID != accountID {
+			continue
+		}
+		wl.Started = wl.Started.In(loc)
+		if day := wl.Date(); day >= startDate && day <= endDate {
+			mine = append(mine, wl)
+		}
+	}
+
+	if err := c.fillIssueKeys(ctx, mine); err != nil {
+		return nil, fmt.Errorf("resolve issue keys: %w", err)
+	}
+	sort.Slice(mine, func(i, j int) bool { return mine[i].Started.Before(mine[j].Started) })
+	return mine, fetchErr
+}
+
+// UpdateWorklog changes the time spent and comment of an existing worklog.
+// The start time is left as is; the remaining estimate follows the project's
+// policy.
+func (c *Client) UpdateWorklog(ctx context.Context, issueKey, worklogID string, timeSpentSeconds int, comment string) error {
+	payload := worklogPayload{
+		TimeSpentSeconds: timeSpentSeconds,
+		Comment:          comment,
+	}
+	query := c.policies.For(issueKey).updateQuery()
+	return c.doJSON(ctx, http.MethodPut, worklogPath(issueKey, worklogID), query, payload, nil)
+}
+
+// DeleteWorklog deletes a worklog, adjusting the remaining estimate by the
+// project's policy.
+func (c *Client) DeleteWorklog(ctx context.Context, issueKey, worklogID string) error {
+	query := c.policies.For(issueKey).deleteQuery()
+	return c.doJSON(ctx, http.MethodDelete, worklogPath(issueKey, worklogID), query, nil, nil)
+}
+
+func worklogPath(issueKey, worklogID string) string {
+	return "/rest/api/2/issue/" + url.PathEscape(issueKey) + "/worklog/" + url.PathEscape(worklogID)
+}
+
+// worklogsForPeriod returns worklogs that may fall into [from, to), not yet
+// filtered by author or exact day. The bulk worklog API is preferred; instances
+// without it, and sites too busy to scan, fall back to a JQL search plus one
+// request per issue.
+func (c *Client) worklogsForPeriod(ctx context.Context, from, to time.Time) ([]Worklog, error) {
+	if !c.legacyWorklogs.Load() {
+		worklogs, err := c.bulkWorklogsSince(ctx, from)
+		switch {
+		case hasStatus(err, http.StatusNotFound) || hasStatus(err, http.StatusMethodNotAllowed):
+			c.legacyWorklogs.Store(true)
+		case errors.Is(err, errTooManyUpdates):
+		default:
+			return worklogs, err
+		}
+	}
+	return c.perIssueWorklogs(ctx, from, to)
+}
+
+// bulkWorklogsSince fetches every worklog updated after since. Worklogs are
+// practically never created before the day they describe, so the update time
+// bounds the period from below.
+func (c *Client) bulkWorklogsSince(ctx context.Context, since time.Time) ([]Worklog, error) {
+	ids, err := c.updatedWorklogIDs(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("list updated worklogs: %w", err)
+	}
+
+	var worklogs []Worklog
+	for start := 0; start < len(ids); start += worklogListBatch {
+		end := min(start+worklogListBatch, len(ids))
+
+		var batch []worklogEntry
+		payload := worklogListRequest{IDs: ids[start:end]}
+		if err := c.doJSON(ctx, http.MethodPost, "/rest/api/2/worklog/list", expandProperties, payload, &batch); err != nil {
+			return nil, fmt.Errorf("fetch worklogs: %w", err)
+		}
+		for _, e := range batch {
+			wl, err := e.toWorklog("")
+			if err != nil {
+				return nil, err
+			}
+			worklogs = append(worklogs, wl)
+		}
+	}
+
+	return worklogs, nil
+}
+
+// updatedWorklogIDs lists the IDs of worklogs updated after since, reading
+// at most maxUpdatedPages pages.
+func (c *Client) updatedWorklogIDs(ctx context.Context, since time.Time) ([]int64, error) {
+	var ids []int64
+	sinceMillis := since.UnixMilli()
+
+	for page := 0; ; page++ {
+		if page == maxUpdatedPages {
+			return nil, errTooManyUpdates
+		}
+
+		q := url.Values{}
+		q.Set("since", strconv.FormatInt(sinceMillis, 10))
+
+		var resp updatedWorklogsResponse
+		if err := c.doJSON(ctx, http.MethodGet, "/rest/api/2/worklog/updated", q, nil, &resp); err != nil {
+			return nil, err
+		}
+
+		for _, v := range resp.Values {
+			ids = append(ids, v.WorklogID)
+		}
+
+		if resp.LastPage || len(resp.Values) == 0 || resp.Until <= sinceMillis {
+			break
+		}
+		sinceMillis = resp.Until
+	}
+
+	return ids, nil
+}
+
+// perIssueWorklogs reads worklogs started within [from, to) on the issues
+// where the current user logged time in the period. Filtering on the start
+// time rather than the update time keeps worklogs entered ahead of the day
+// they describe.
+func (c *Client) perIssueWorklogs(ctx context.Context, from, to time.Time) ([]Worklog, error) {
+	startDate := from.Format("2006-01-02")
+	endDate := to.AddDate(0, 0, -1).Format("2006-01-02")
+	jql := fmt.Sprintf(`worklogDate >= "%s" AND worklogDate <= "%s" AND worklogAuthor = currentUser()`, startDate, endDate)
+	issues, err := c.searchIssues(ctx, jql)
+	if err != nil {
+		return nil, fmt.Errorf("search worklogs: %w", err)
+	}
+
+	keys := make([]string, len(issues))
+	for i, issue := range issues {
+		keys[i] = issue.Key
+	}
+	return c.fetchIssueWorklogs(ctx, keys, from, to)
+}
+
+// getIssueWorklogs reads all pages of an issue's worklogs started within [from, to).
+func (c *Client) getIssueWorklogs(ctx context.Context, issueKey string, from, to time.Time) ([]Worklog, error) {
+	var worklogs []Worklog
+	path := "/rest/api/2/issue/" + url.PathEscape(issueKey) + "/worklog"
+	startAt := 0
+
+	for {
+		q := url.Values{}
+		q.Set("startAt", strconv.Itoa(startAt))
+		q.Set("maxResults", strconv.Itoa(worklogPageSize))
+		q.Set("expand", "properties")
+		if !from.IsZero() {
+			q.Set("startedAfter", strconv.FormatInt(from.UnixMilli(), 10))
+		}
+		if !to.IsZero() {
+			q.Set("startedBefore", strconv.FormatInt(to.UnixMilli(), 10))
+		}
+
+		var wlResp worklogListResponse
+		if err := c.doJSON(ctx, http.MethodGet, path, q, nil, &wlResp); err != nil {
+			return nil, err
+		}
+
+		for _, e := range wlResp.Worklogs {
+			wl, err := e.toWorklog(issueKey)
+			if err != nil {
+				return nil, err
+			}
+			worklogs = append(worklogs, wl)
+		}
+
+		startAt += len(wlResp.Worklogs)
+		if len(wlResp.Worklogs) == 0 || startAt >= wlResp.Total {
+			break
+		}
+	}
+
+	return worklogs, nil
+}
+
+// fillIssueKeys sets IssueKey on worklogs that only carry an issue ID,
+// as returned by the bulk worklog API.
+func (c *Client) fillIssueKeys(ctx context.Context, worklogs []Worklog) error {
+	seen := make(map[string]bool)
+	var ids []string
+	for _, wl := range worklogs {
+		if wl.IssueKey == "" && wl.IssueID != "" && !seen[wl.IssueID] {
+			seen[wl.IssueID] = true
+			ids = append(ids, wl.IssueID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	keys, err := c.IssueKeys(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i := range worklogs {
+		if worklogs[i].IssueKey == "" {
+			worklogs[i].IssueKey = keys[worklogs[i].IssueID]
+		}
+	}
+	return nil
+}
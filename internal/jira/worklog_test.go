@@ -0,0 +1,311 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetLoggedSecondsForDateRangeBulk(t *testing.T) {
+	var requests, updated, lists atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Path {
+		case "/rest/api/2/myself":
+			w.Write([]byte(`{"accountId":"me","timeZone":"UTC"}`))
+		case "/rest/api/2/worklog/updated":
+			// 1500 updated worklogs over two pages.
+			since, _ := strconv.ParseInt(r.URL.Query().Get("since"), 10, 64)
+			first, count, last := 1, 1000, false
+			if updated.Add(1) == 2 {
+				first, count, last = 1001, 500, true
+			}
+			values := make([]updatedWorklog, count)
+			for i := range values {
+				values[i].WorklogID = int64(first + i)
+			}
+			json.NewEncoder(w).Encode(updatedWorklogsResponse{Values: values, Until: since + 1, LastPage: last})
+		case "/rest/api/2/worklog/list":
+			lists.Add(1)
+			var req worklogListRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			if len(req.IDs) > worklogListBatch {
+				t.Errorf("got %d ids in one batch", len(req.IDs))
+			}
+			var entries []string
+			for _, id := range req.IDs {
+				author, started := "other", "2025-03-03T10:00:00.000+0000"
+				switch id {
+				case 1:
+					author = "me"
+				case 1200:
+					author = "me"
+					started = "2025-03-09T10:00:00.000+0000"
+				}
+				entries = append(entries, fmt.Sprintf(`{"id":"%d","issueId":"10","author":{"accountId":"%s"},"timeSpentSeconds":3600,"started":"%s"}`, id, author, started))
+			}
+			w.Write([]byte("[" + strings.Join(entries, ",") + "]"))
+		case "/rest/api/3/search/jql":
+			if jql := r.URL.Query().Get("jql"); jql != "id in (10)" {
+				t.Errorf("unexpected JQL %q", jql)
+			}
+			w.Write([]byte(`{"issues":[{"id":"10","key":"A-1","fields":{}}],"isLast":true}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", "token", AuthBearer)
+	worklogs, err := c.ListMyWorklogs(context.Background(), "2025-03-03", "2025-03-07")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(worklogs) != 1 || worklogs[0].ID != "1" || worklogs[0].IssueKey != "A-1" {
+		t.Errorf("got %+v", worklogs)
+	}
+	if n := lists.Load(); n != 2 {
+		t.Errorf("made %d list requests, want 2", n)
+	}
+	if n := requests.Load(); n != 6 {
+		t.Errorf("made %d requests, want 6 (myself, 2 updated, 2 list, search)", n)
+	}
+}
+
+func TestGetLoggedSecondsForDateRangeFallsBackPerIssue(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Path {
+		case "/rest/api/2/myself":
+			w.Write([]byte(`{"accountId":"me","timeZone":"UTC"}`))
+		case "/rest/api/2/worklog/updated":
+			http.NotFound(w, r)
+		case "/rest/api/3/search/jql":
+			jql := r.URL.Query().Get("jql")
+			if !strings.Contains(jql, "worklogAuthor = currentUser()") || !strings.Contains(jql, `worklogDate <= "2025-03-07"`) {
+				t.Errorf("unexpected JQL %q", jql)
+			}
+			w.Write([]byte(`{"issues":[{"id":"10","key":"A-1","fields":{}}],"isLast":true}`))
+		case "/rest/api/2/issue/A-1/worklog":
+			// Colleagues' worklogs on the same issue, and one of mine logged
+			// in advance of the day it describes.
+			w.Write([]byte(`{"startAt":0,"total":4,"worklogs":[
+				{"id":"1","author":{"accountId":"me"},"timeSpentSeconds":3600,"started":"2025-03-03T10:00:00.000+0000"},
+				{"id":"2","author":{"accountId":"other"},"timeSpentSeconds":7200,"started":"2025-03-03T10:00:00.000+0000"},
+				{"id":"3","author":{"accountId":"other"},"timeSpentSeconds":7200,"started":"2025-03-04T10:00:00.000+0000"},
+				{"id":"4","author":{"accountId":"me"},"timeSpentSeconds":1800,"started":"2025-03-07T10:00:00.000+0000"}]}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", "token", AuthBearer)
+	got, err := c.GetLoggedSecondsForDateRange(context.Background(), "2025-03-03", "2025-03-07")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got["2025-03-03"] != 3600 || got["2025-03-07"] != 1800 {
+		t.Errorf("got %v", got)
+	}
+	if n := requests.Load(); n != 4 {
+		t.Errorf("made %d requests, want 4 (myself, updated, search, one issue)", n)
+	}
+
+	// The bulk API is not tried again.
+	requests.Store(0)
+	if _, err := c.GetLoggedSecondsForDateRange(context.Background(), "2025-03-03", "2025-03-07"); err != nil {
+		t.Fatal(err)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("made %d requests on the second read, want 2 (search, one issue)", n)
+	}
+}
+
+func TestWorklogsForPeriodCapsBulkScan(t *testing.T) {
+	var updated, perIssue atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/2/worklog/updated":
+			since, _ := strconv.ParseInt(r.URL.Query().Get("since"), 10, 64)
+			fmt.Fprintf(w, `{"values":[{"worklogId":%d}],"until":%d,"lastPage":false}`, updated.Add(1), since+1)
+		case "/rest/api/3/search/jql":
+			w.Write([]byte(`{"issues":[{"id":"10","key":"A-1","fields":{}}],"isLast":true}`))
+		case "/rest/api/2/issue/A-1/worklog":
+			perIssue.Add(1)
+			w.Write([]byte(`{"startAt":0,"total":0,"worklogs":[]}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", "token", AuthBearer)
+	from := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	if _, err := c.worklogsForPeriod(context.Background(), from, from.AddDate(0, 0, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if n := updated.Load(); n != maxUpdatedPages {
+		t.Errorf("read %d updated pages, want %d", n, maxUpdatedPages)
+	}
+	if perIssue.Load() != 1 {
+		t.Errorf("per-issue fallback not used")
+	}
+	if c.legacyWorklogs.Load() {
+		t.Errorf("a busy site must not disable the bulk API for good")
+	}
+}
+
+func TestGetIssueWorklogsPaginates(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("startedAfter") == "" {
+			t.Errorf("startedAfter not set")
+		}
+		switch r.URL.Query().Get("startAt") {
+		case "0":
+			w.Write([]byte(`{"startAt":0,"total":3,"worklogs":[
+				{"id":"1","comment":"a","timeSpentSeconds":60,"started":"2025-03-03T10:00:00.000+0300"},
+				{"id":"2","comment":"b","timeSpentSeconds":60,"started":"2025-03-03T11:00:00.000+0300"}]}`))
+		case "2":
+			w.Write([]byte(`{"startAt":2,"total":3,"worklogs":[
+				{"id":"3","comment":"c","timeSpentSeconds":60,"started":"2025-03-04T10:00:00.000+0300"}]}`))
+		default:
+			t.Errorf("unexpected startAt %s", r.URL.Query().Get("startAt"))
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", "token", AuthBearer)
+	from := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	worklogs, err := c.getIssueWorklogs(context.Background(), "A-1", from, from.AddDate(0, 0, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(worklogs) != 3 {
+		t.Fatalf("got %d worklogs, want 3", len(worklogs))
+	}
+	if wl := worklogs[2]; wl.ID != "3" || wl.IssueKey != "A-1" || wl.Comment != "c" || wl.Date() != "2025-03-04" {
+		t.Errorf("unexpected worklog %+v", wl)
+	}
+}
+
+func TestGetLoggedSecondsForDateRangeUsesUserZone(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/2/myself":
+			w.Write([]byte(`{"accountId":"me","timeZone":"Europe/Moscow"}`))
+		case "/rest/api/2/worklog/updated":
+			w.Write([]byte(`{"values":[{"worklogId":1}],"lastPage":true}`))
+		case "/rest/api/2/worklog/list":
+			w.Write([]byte(`[{"id":"1","author":{"accountId":"me"},"timeSpentSeconds":3600,"started":"2025-03-03T22:30:00.000+0000"}]`))
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", "token", AuthBearer)
+	got, err := c.GetLoggedSecondsForDateRange(context.Background(), "2025-03-03", "2025-03-04")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["2025-03-04"] != 3600 {
+		t.Errorf("got %v, want the worklog on 2025-03-04 Moscow time", got)
+	}
+
+	started, err := c.WorkdayStart(context.Background(), "2025-03-04")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := started.Format(jiraTimeLayout); s != "2025-03-04T09:00:00.000+0300" {
+		t.Errorf("WorkdayStart = %s", s)
+	}
+}
+
+func TestUpdateWorklog(t *testing.T) {
+	var body map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/rest/api/2/issue/A-1/worklog/7" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"id":"7"}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", "token", AuthBearer)
+	if err := c.UpdateWorklog(context.Background(), "A-1", "7", 5400, "review"); err != nil {
+		t.Fatal(err)
+	}
+	if body["timeSpentSeconds"] != float64(5400) || body["comment"] != "review" {
+		t.Errorf("got body %v", body)
+	}
+	if _, ok := body["started"]; ok {
+		t.Errorf("start time must be left as is: %v", body)
+	}
+}
+
+func TestDeleteWorklog(t *testing.T) {
+	var method, path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", "token", AuthBearer)
+	if err := c.DeleteWorklog(context.Background(), "10001", "7"); err != nil {
+		t.Fatal(err)
+	}
+	if method != http.MethodDelete || path != "/rest/api/2/issue/10001/worklog/7" {
+		t.Errorf("got %s %s", method, path)
+	}
+}
+
+func TestListMyWorklogsFiltersAuthorAndDate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/2/myself":
+			w.Write([]byte(`{"accountId":"me","timeZone":"Europe/Moscow"}`))
+		case "/rest/api/2/worklog/updated":
+			w.Write([]byte(`{"values":[{"worklogId":1},{"worklogId":2},{"worklogId":3},{"worklogId":4},{"worklogId":5}],"lastPage":true}`))
+		case "/rest/api/2/worklog/list":
+			// Moscow is UTC+3: worklog 3 is on 03-05 and worklog 4 on 03-02 there.
+			w.Write([]byte(`[
+				{"id":"1","issueId":"10","author":{"accountId":"me"},"timeSpentSeconds":60,"started":"2025-03-04T20:30:00.000+0000"},
+				{"id":"2","issueId":"10","author":{"accountId":"other"},"timeSpentSeconds":60,"started":"2025-03-03T10:00:00.000+0000"},
+				{"id":"3","issueId":"10","author":{"accountId":"me"},"timeSpentSeconds":60,"started":"2025-03-04T21:30:00.000+0000"},
+				{"id":"4","issueId":"10","author":{"accountId":"me"},"timeSpentSeconds":60,"started":"2025-03-02T20:30:00.000+0000"},
+				{"id":"5","issueId":"20","author":{"accountId":"me"},"timeSpentSeconds":60,"started":"2025-03-02T21:30:00.000+0000"}
+			]`))
+		case "/rest/api/3/search/jql":
+			w.Write([]byte(`{"issues":[{"id":"10","key":"A-1","fields":{}},{"id":"20","key":"B-2","fields":{}}],"isLast":true}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", "token", AuthBearer)
+	got, err := c.ListMyWorklogs(context.Background(), "2025-03-03", "2025-03-04")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, wl := range got {
+		ids = append(ids, wl.ID+":"+wl.IssueKey+":"+wl.Date())
+	}
+	if want := "5:B-2:2025-03-03,1:A-1:2025-03-04"; strings.Join(ids, ",") != want {
+		t.Errorf("got %v, want %s", ids, want)
+	}
+}
@@ -0,0 +1,180 @@
+// Package journal keeps local state under ~/.secretary: a record of worklogs
+// created by sj, grouped by session, so that a submission can be undone, and
+// queues of confirmed worklogs that have not been submitted yet.
+package journal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// maxSessions is how many recent sessions the journal keeps.
+const maxSessions = 50
+
+// Entry is a worklog created during a session. Replaced is set when the
+// worklog superseded an existing one that was deleted, so undo can bring it back.
+type Entry struct {
+	IssueKey         string    `json:"issue_key"`
+	WorklogID        string    `json:"worklog_id"`
+	TimeSpentSeconds int       `json:"time_spent_seconds"`
+	Comment          string    `json:"comment"`
+	Started          time.Time `json:"started"`
+	Replaced         *Replaced `json:"replaced,omitempty"`
+}
+
+// Replaced is a worklog deleted in favour of a session's entry.
+type Replaced struct {
+	IssueKey         string    `json:"issue_key"`
+	TimeSpentSeconds int       `json:"time_spent_seconds"`
+	Comment          string    `json:"comment"`
+	Started          time.Time `json:"started"`
+}
+
+// Session groups the worklogs created by one run of sj. Backend is the sink
+// the worklogs were written to ("jira" or "tempo"); worklog IDs only make
+// sense for that backend.
+type Session struct {
+	ID        string    `json:"id"`
+	Backend   string    `json:"backend"`
+	CreatedAt time.Time `json:"created_at"`
+	Entries   []Entry   `json:"entries"`
+}
+
+// TotalSeconds is the time logged in the session.
+func (s Session) TotalSeconds() int {
+	total := 0
+	for _, e := range s.Entries {
+		total += e.TimeSpentSeconds
+	}
+	return total
+}
+
+// Journal is a JSON file with sessions, oldest first.
+type Journal struct {
+	path string
+	mu   sync.Mutex
+}
+
+func New(path string) *Journal {
+	return &Journal{path: path}
+}
+
+// Record appends an entry to the session, creating the session if needed.
+func (j *Journal) Record(sessionID, backend string, entry Entry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	sessions, err := j.load()
+	if err != nil {
+		return err
+	}
+
+	idx := -1
+	for i := range sessions {
+		if sessions[i].ID == sessionID {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		sessions = append(sessions, Session{ID: sessionID, Backend: backend, CreatedAt: time.Now()})
+		idx = len(sessions) - 1
+	}
+	sessions[idx].Entries = append(sessions[idx].Entries, entry)
+
+	if len(sessions) > maxSessions {
+		sessions = sessions[len(sessions)-maxSessions:]
+	}
+	return j.save(sessions)
+}
+
+// Sessions returns the recorded sessions, most recent first.
+func (j *Journal) Sessions() ([]Session, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	sessions, err := j.load()
+	if err != nil {
+		return nil, err
+	}
+	for i, k := 0, len(sessions)-1; i < k; i, k = i+1, k-1 {
+		sessions[i], sessions[k] = sessions[k], sessions[i]
+	}
+	return sessions, nil
+}
+
+// Forget removes the given worklogs from a session, and the session itself
+// once it has no worklogs left.
+func (j *Journal) Forget(sessionID string, worklogIDs []string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	sessions, err := j.load()
+	if err != nil {
+		return err
+	}
+
+	drop := make(map[string]bool, len(worklogIDs))
+	for _, id := range worklogIDs {
+		drop[id] = true
+	}
+
+	kept := sessions[:0]
+	for _, s := range sessions {
+		if s.ID == sessionID {
+			entries := s.Entries[:0]
+			for _, e := range s.Entries {
+				if !drop[e.WorklogID] {
+					entries = append(entries, e)
+				}
+			}
+			s.Entries = entries
+			if len(s.Entries) == 0 {
+				continue
+			}
+		}
+		kept = append(kept, s)
+	}
+	return j.save(kept)
+}
+
+func (j *Journal) load() ([]Session, error) {
+	var sessions []Session
+	err := readJSON(j.path, &sessions)
+	return sessions, err
+}
+
+func (j *Journal) save(sessions []Session) error {
+	return writeJSON(j.path, sessions)
+}
+
+// readJSON decodes the file into v; a missing file leaves v untouched.
+func readJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("invalid file %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+func writeJSON(path string, v any) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("cannot create directory: %w", err)
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
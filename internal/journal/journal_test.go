@@ -0,0 +1,86 @@
+package journal
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRecordAndForget(t *testing.T) {
+	j := New(filepath.Join(t.TempDir(), "journal.json"))
+
+	if err := j.Record("s1", "jira", Entry{IssueKey: "A-1", WorklogID: "1", TimeSpentSeconds: 3600}); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Record("s1", "jira", Entry{IssueKey: "A-2", WorklogID: "2", TimeSpentSeconds: 1800}); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Record("s2", "tempo", Entry{IssueKey: "A-3", WorklogID: "3", TimeSpentSeconds: 600}); err != nil {
+		t.Fatal(err)
+	}
+
+	sessions, err := j.Sessions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 2 || sessions[0].ID != "s2" || sessions[1].TotalSeconds() != 5400 {
+		t.Fatalf("got %+v", sessions)
+	}
+
+	if err := j.Forget("s1", []string{"1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Forget("s2", []string{"3"}); err != nil {
+		t.Fatal(err)
+	}
+
+	sessions, _ = j.Sessions()
+	if len(sessions) != 1 || len(sessions[0].Entries) != 1 || sessions[0].Entries[0].WorklogID != "2" {
+		t.Errorf("got %+v", sessions)
+	}
+}
+
+func TestRecordKeepsReplacedWorklog(t *testing.T) {
+	j := New(filepath.Join(t.TempDir(), "journal.json"))
+
+	started := time.Date(2025, 3, 3, 10, 0, 0, 0, time.UTC)
+	old := &Replaced{IssueKey: "A-1", TimeSpentSeconds: 3600, Comment: "review", Started: started}
+	if err := j.Record("s1", "jira", Entry{IssueKey: "A-1", WorklogID: "2", TimeSpentSeconds: 5400, Replaced: old}); err != nil {
+		t.Fatal(err)
+	}
+
+	sessions, err := j.Sessions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := sessions[0].Entries[0].Replaced
+	if got == nil || got.IssueKey != "A-1" || got.TimeSpentSeconds != 3600 || got.Comment != "review" || !got.Started.Equal(started) {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestQueue(t *testing.T) {
+	q := NewQueue(filepath.Join(t.TempDir(), "failed.json"))
+
+	items, err := q.Items()
+	if err != nil || len(items) != 0 {
+		t.Fatalf("Items() on a missing file = %v, %v", items, err)
+	}
+
+	if err := q.Add(Pending{IssueKey: "A-1"}, Pending{IssueKey: "A-2"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Add(Pending{IssueKey: "A-3"}); err != nil {
+		t.Fatal(err)
+	}
+	if items, _ = q.Items(); len(items) != 3 || items[2].IssueKey != "A-3" {
+		t.Fatalf("got %+v", items)
+	}
+
+	if err := q.Save(items[1:2]); err != nil {
+		t.Fatal(err)
+	}
+	if items, _ = q.Items(); len(items) != 1 || items[0].IssueKey != "A-2" {
+		t.Errorf("got %+v", items)
+	}
+}
@@ -0,0 +1,65 @@
+package journal
+
+import (
+	"sync"
+	"time"
+
+	"go-secretary/internal/jira"
+)
+
+// Pending is a confirmed worklog that has not reached the backend yet.
+// ReplacesIssueKey and ReplacesWorklogID name an existing worklog to delete
+// once this one is created; Visibility is the user's override, if any.
+type Pending struct {
+	IssueKey          string           `json:"issue_key"`
+	TimeSpentSeconds  int              `json:"time_spent_seconds"`
+	Comment           string           `json:"comment"`
+	Started           time.Time        `json:"started"`
+	Error             string           `json:"error,omitempty"`
+	ReplacesIssueKey  string           `json:"replaces_issue_key,omitempty"`
+	ReplacesWorklogID string           `json:"replaces_worklog_id,omitempty"`
+	Visibility        *jira.Visibility `json:"visibility,omitempty"`
+}
+
+// Queue is a JSON file with pending worklogs, kept until they are submitted.
+type Queue struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewQueue(path string) *Queue {
+	return &Queue{path: path}
+}
+
+// Items returns the queued worklogs in the order they were added.
+func (q *Queue) Items() ([]Pending, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var items []Pending
+	err := readJSON(q.path, &items)
+	return items, err
+}
+
+// Add appends worklogs to the queue.
+func (q *Queue) Add(items ...Pending) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var queued []Pending
+	if err := readJSON(q.path, &queued); err != nil {
+		return err
+	}
+	return writeJSON(q.path, append(queued, items...))
+}
+
+// Save replaces the queue contents.
+func (q *Queue) Save(items []Pending) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if items == nil {
+		items = []Pending{}
+	}
+	return writeJSON(q.path, items)
+}
@@ -0,0 +1,210 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"go-secretary/internal/gemini"
+	"go-secretary/internal/jira"
+	"go-secretary/internal/journal"
+	"go-secretary/internal/timeparse"
+	"go-secretary/internal/ui"
+
+	"github.com/charmbracelet/huh"
+	"github.com/pterm/pterm"
+)
+
+// similarTimeSeconds is how far apart two durations may be to count as the same work.
+const similarTimeSeconds = 15 * 60
+
+type duplicateAction int
+
+const (
+	duplicateSkip duplicateAction = iota
+	duplicateReplace
+	duplicateKeep
+)
+
+// submission is one worklog row on its way to the sink. Replaces is set when
+// the row supersedes a worklog that already exists in the sink; Visibility, when
+// set, overrides the project's default visibility.
+type submission struct {
+	gemini.ParsedWorkLog
+	Started    time.Time
+	Replaces   *jira.Worklog
+	Visibility *jira.Visibility
+}
+
+// resolveDuplicates compares the rows with worklogs the user already has on
+// that day and asks what to do with each likely duplicate, so a session can
+// be rerun safely after a crash.
+func (r *Runner) resolveDuplicates(ctx context.Context, workLogs []gemini.ParsedWorkLog, date string) []submission {
+	submissions := make([]submission, len(workLogs))
+	for i, log := range workLogs {
+		submissions[i] = submission{ParsedWorkLog: log}
+	}
+
+	if date == "" {
+		date = r.jira.Today(ctx)
+	}
+	result := r.skipDuplicates(ctx, submissions, date)
+
+	if len(result) != len(submissions) && len(result) > 0 {
+		ui.PrintSummary(submissionLogs(result), nil)
+	}
+	return result
+}
+
+// skipDuplicates drops rows that repeat a worklog already logged on date
+// exactly, and asks about rows that only look similar. If the worklogs cannot
+// be read, all rows are kept.
+func (r *Runner) skipDuplicates(ctx context.Context, submissions []submission, date string) []submission {
+	spinner, _ := pterm.DefaultSpinner.WithRemoveWhenDone(true).Start("Проверяю, не залогировано ли это уже...")
+	existing, err := r.sink.ListMyWorklogs(ctx, date, date)
+	spinner.Stop()
+	if err = warnPartialWorklogs(err); err != nil {
+		ui.PrintError("Не удалось проверить дубликаты: " + describeError(err))
+		return submissions
+	}
+
+	used := make([]bool, len(existing))
+	var result []submission
+	for _, sub := range submissions {
+		idx := findDuplicate(sub.ParsedWorkLog, existing, used)
+		if idx < 0 {
+			result = append(result, sub)
+			continue
+		}
+		used[idx] = true
+		dup := existing[idx]
+
+		if isSameWorklog(sub, dup) {
+			ui.PrintStatus(fmt.Sprintf("%s %s уже залогировано, пропускаю.", sub.IssueKey, timeparse.Format(sub.TimeSeconds)))
+			continue
+		}
+
+		switch askDuplicateAction(sub.ParsedWorkLog, dup) {
+		case duplicateSkip:
+			continue
+		case duplicateReplace:
+			sub.Replaces = &dup
+		}
+		result = append(result, sub)
+	}
+	return result
+}
+
+// skipLogged runs skipDuplicates over saved rows day by day, keeping their
+// order, so that retrying a row that did reach the sink does not log it twice.
+func (r *Runner) skipLogged(ctx context.Context, items []journal.Pending) []submission {
+	loc := r.jira.Location(ctx)
+	var dates []string
+	byDate := make(map[string][]submission)
+	for _, p := range items {
+		date := p.Started.In(loc).Format("2006-01-02")
+		if _, ok := byDate[date]; !ok {
+			dates = append(dates, date)
+		}
+		byDate[date] = append(byDate[date], submissionOf(p))
+	}
+	var submissions []submission
+	for _, date := range dates {
+		submissions = append(submissions, r.skipDuplicates(ctx, byDate[date], date)...)
+	}
+	return submissions
+}
+
+// isSameWorklog reports a worklog that was certainly created from this row,
+// e.g. by an earlier attempt whose response was lost.
+func isSameWorklog(sub submission, wl jira.Worklog) bool {
+	return !sub.Started.IsZero() && wl.Started.Equal(sub.Started) &&
+		wl.TimeSpentSeconds == sub.TimeSeconds && wl.Comment == sub.Description
+}
+
+// findDuplicate returns the index of an unused existing worklog on the same
+// issue with a similar duration and either a similar comment or exactly the
+// same duration, or -1.
+func findDuplicate(log gemini.ParsedWorkLog, existing []jira.Worklog, used []bool) int {
+	for i, wl := range existing {
+		if used[i] || !strings.EqualFold(wl.IssueKey, log.IssueKey) {
+			continue
+		}
+		diff := wl.TimeSpentSeconds - log.TimeSeconds
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff <= similarTimeSeconds && (diff == 0 || similarComment(wl.Comment, log.Description)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// minContainedRunes is the shortest comment that matches as a part of a
+// longer one; shorter comments such as "ок" only match exactly.
+const minContainedRunes = 5
+
+// similarComment reports comments that are equal up to case, spacing and
+// punctuation, or where one is a run of whole words of the other, e.g.
+// "Код-ревью" and "Код-ревью PR 42".
+func similarComment(a, b string) bool {
+	a, b = commentWords(a), commentWords(b)
+	if a == "" || b == "" {
+		return false
+	}
+	if a == b {
+		return true
+	}
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+	if utf8.RuneCountInString(b) < minContainedRunes {
+		return false
+	}
+	return strings.Contains(" "+a+" ", " "+b+" ")
+}
+
+// commentWords lowercases the comment and keeps only its words, separated by
+// single spaces.
+func commentWords(s string) string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " ")
+}
+
+func askDuplicateAction(log gemini.ParsedWorkLog, dup jira.Worklog) duplicateAction {
+	pterm.Warning.Printfln("%s %s похоже уже залогировано: %s в %s «%s»",
+		log.IssueKey, timeparse.Format(log.TimeSeconds),
+		timeparse.Format(dup.TimeSpentSeconds), dup.Started.Format("15:04"), dup.Comment)
+
+	action := duplicateSkip
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[duplicateAction]().
+				Title("Что сделать с "+log.IssueKey+"?").
+				Options(
+					huh.NewOption("Пропустить (уже залогировано)", duplicateSkip),
+					huh.NewOption(fmt.Sprintf("Заменить существующий ворклог (%s)", timeparse.Format(dup.TimeSpentSeconds)), duplicateReplace),
+					huh.NewOption("Оставить оба", duplicateKeep),
+				).
+				Value(&action),
+		),
+	)
+	if err := form.Run(); err != nil {
+		return duplicateSkip
+	}
+	return action
+}
+
+func submissionLogs(submissions []submission) []gemini.ParsedWorkLog {
+	logs := make([]gemini.ParsedWorkLog, len(submissions))
+	for i, sub := range submissions {
+		logs[i] = sub.ParsedWorkLog
+	}
+	return logs
+}
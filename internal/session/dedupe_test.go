@@ -0,0 +1,61 @@
+package session
+
+import (
+	"testing"
+
+	"go-secretary/internal/gemini"
+	"go-secretary/internal/jira"
+)
+
+func TestFindDuplicate(t *testing.T) {
+	existing := []jira.Worklog{
+		{IssueKey: "PROJ-1", TimeSpentSeconds: 7200, Comment: "Код-ревью"},
+		{IssueKey: "PROJ-2", TimeSpentSeconds: 3600, Comment: "Созвон  с командой"},
+	}
+
+	tests := []struct {
+		name string
+		log  gemini.ParsedWorkLog
+		want int
+	}{
+		{"same time", gemini.ParsedWorkLog{IssueKey: "PROJ-1", TimeSeconds: 7200}, 0},
+		{"similar time and comment", gemini.ParsedWorkLog{IssueKey: "proj-1", TimeSeconds: 7200 + 600, Description: "код-ревью PR 42"}, 0},
+		{"similar time only", gemini.ParsedWorkLog{IssueKey: "PROJ-1", TimeSeconds: 7200 + 600, Description: "Деплой"}, -1},
+		{"same comment, other time", gemini.ParsedWorkLog{IssueKey: "PROJ-2", TimeSeconds: 7200, Description: "созвон с командой"}, -1},
+		{"same comment, similar time", gemini.ParsedWorkLog{IssueKey: "PROJ-2", TimeSeconds: 3600 - 900, Description: "созвон с командой"}, 1},
+		{"other issue", gemini.ParsedWorkLog{IssueKey: "PROJ-3", TimeSeconds: 3600}, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			used := make([]bool, len(existing))
+			if got := findDuplicate(tt.log, existing, used); got != tt.want {
+				t.Errorf("findDuplicate() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	used := []bool{true, false}
+	if got := findDuplicate(gemini.ParsedWorkLog{IssueKey: "PROJ-1", TimeSeconds: 7200}, existing, used); got != -1 {
+		t.Errorf("findDuplicate() matched an already used worklog: %d", got)
+	}
+}
+
+func TestSimilarComment(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"Код-ревью", "код-ревью PR 42", true},
+		{"Созвон  с командой", "созвон с командой.", true},
+		{"ок", "ок", true},
+		{"ок", "ок, смотрю логи", false},
+		{"ревью", "ревьюер не ответил", false},
+		{"деплой", "", false},
+	}
+	for _, tt := range tests {
+		if got := similarComment(tt.a, tt.b); got != tt.want {
+			t.Errorf("similarComment(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
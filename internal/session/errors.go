@@ -0,0 +1,61 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+
+	"go-secretary/internal/jira"
+)
+
+// describeError turns a Jira failure into an actionable message in Russian.
+// Errors from other sources are returned as is.
+func describeError(err error) string {
+	var apiErr *jira.APIError
+	if !errors.As(err, &apiErr) {
+		if jira.IsTLSError(err) {
+			return "не удалось установить защищённое соединение с Jira, проверь сертификаты: jira_ca_cert, jira_client_cert (" + err.Error() + ")"
+		}
+		if isOffline(err) {
+			return "не удалось связаться с сервером, проверь сеть или VPN (" + err.Error() + ")"
+		}
+		return err.Error()
+	}
+
+	var msg string
+	switch {
+	case jira.IsUnauthorized(err):
+		msg = "Jira не приняла учётные данные. Проверь токен и способ авторизации: sj config"
+	case jira.IsForbidden(err):
+		msg = "недостаточно прав в Jira для этого действия"
+	case jira.IsNotFound(err):
+		msg = "Jira не нашла задачу или ресурс, либо у тебя нет к нему доступа"
+	case jira.IsRateLimited(err):
+		msg = "Jira ограничила частоту запросов, подожди минуту и попробуй снова"
+	case jira.IsServerError(err):
+		msg = "Jira временно недоступна, попробуй позже"
+	default:
+		msg = "Jira отклонила запрос"
+	}
+
+	if details := apiErr.Details(); details != "" {
+		msg += ": " + details
+	}
+	if apiErr.RequestID != "" {
+		msg += " (request ID " + apiErr.RequestID + ")"
+	}
+	return msg
+}
+
+// isOffline reports a failure to reach the server at all, as opposed to the
+// server rejecting the request.
+func isOffline(err error) bool {
+	var apiErr *jira.APIError
+	if err == nil || errors.As(err, &apiErr) || errors.Is(err, context.Canceled) || jira.IsTLSError(err) {
+		return false
+	}
+	var netErr net.Error
+	var urlErr *url.Error
+	return errors.As(err, &netErr) || errors.As(err, &urlErr)
+}
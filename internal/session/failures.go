@@ -0,0 +1,316 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-secretary/internal/gemini"
+	"go-secretary/internal/jira"
+	"go-secretary/internal/journal"
+	"go-secretary/internal/timeparse"
+	"go-secretary/internal/ui"
+
+	"github.com/charmbracelet/huh"
+	"github.com/pterm/pterm"
+)
+
+type failureAction int
+
+const (
+	failureRetry failureAction = iota
+	failureEdit
+	failureSave
+	failureDrop
+)
+
+// failedSubmission is a row the sink rejected, with the reason.
+type failedSubmission struct {
+	submission
+	err error
+}
+
+// postSubmissions sends the rows to the sink, records created worklogs in the
+// journal and returns the rows that failed.
+func (r *Runner) postSubmissions(ctx context.Context, submissions []submission) []failedSubmission {
+	var failed []failedSubmission
+	var offline error
+
+	pterm.Println()
+	for _, sub := range submissions {
+		if offline != nil {
+			// Once the server is unreachable, do not wait for a timeout on every row.
+			failed = append(failed, failedSubmission{submission: sub, err: offline})
+			continue
+		}
+
+		spinner, _ := pterm.DefaultSpinner.Start("Логирую " + sub.IssueKey + "...")
+		worklogID, err := r.logWork(ctx, sub)
+		spinner.Stop()
+		ui.PrintLogResult(sub.IssueKey, err == nil)
+		if err != nil {
+			ui.PrintError("  " + describeError(err))
+			failed = append(failed, failedSubmission{submission: sub, err: err})
+			if isOffline(err) {
+				offline = err
+			}
+		} else {
+			var replaced *jira.Worklog
+			if sub.Replaces != nil {
+				// The old worklog is removed only after the new one is in place.
+				if err := r.sink.DeleteWorklog(ctx, worklogIssue(*sub.Replaces), sub.Replaces.ID); err != nil {
+					ui.PrintError("  не удалось удалить старый ворклог: " + describeError(err))
+				} else {
+					replaced = sub.Replaces
+				}
+			}
+			r.recordWorklog(sub, worklogID, replaced)
+		}
+		time.Sleep(300 * time.Millisecond)
+	}
+	return failed
+}
+
+// logWork sends one row, with its visibility override if the sink supports it.
+func (r *Runner) logWork(ctx context.Context, sub submission) (string, error) {
+	if vs, ok := r.sink.(visibilitySink); ok && sub.Visibility != nil {
+		return vs.LogWorkWithVisibility(ctx, sub.IssueKey, sub.TimeSeconds, sub.Description, sub.Started, sub.Visibility)
+	}
+	return r.sink.LogWork(ctx, sub.IssueKey, sub.TimeSeconds, sub.Description, sub.Started)
+}
+
+// recoverFailures shows the failed rows and lets the user retry them, fix a
+// row first, keep them for sj retry or drop them. It returns the rows to keep.
+func (r *Runner) recoverFailures(ctx context.Context, failed []failedSubmission) []failedSubmission {
+	for len(failed) > 0 {
+		ui.PrintFailedWorklogs(failedRows(failed))
+
+		switch askFailureAction() {
+		case failureRetry:
+			retried := r.postSubmissions(ctx, submissionsOf(failed))
+			ui.PrintSubmissionReport(len(failed)-len(retried), len(failed))
+			failed = retried
+		case failureEdit:
+			if idx, ok := selectFailed(failed); ok {
+				r.editSubmission(ctx, &failed[idx].submission)
+			}
+		case failureSave:
+			return failed
+		case failureDrop:
+			if ui.ConfirmYesNo(fmt.Sprintf("Отбросить %d ворклог(ов)? Они не будут залогированы.", len(failed))) {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+func askFailureAction() failureAction {
+	action := failureRetry
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[failureAction]().
+				Title("Что сделать с неотправленными ворклогами?").
+				Options(
+					huh.NewOption("Повторить отправку", failureRetry),
+					huh.NewOption("Исправить строку", failureEdit),
+					huh.NewOption("Сохранить и повторить позже (sj retry)", failureSave),
+					huh.NewOption("Отбросить", failureDrop),
+				).
+				Value(&action),
+		),
+	)
+	if err := form.Run(); err != nil {
+		// Interrupted: keep the rows rather than lose them.
+		return failureSave
+	}
+	return action
+}
+
+func selectFailed(failed []failedSubmission) (int, bool) {
+	if len(failed) == 1 {
+		return 0, true
+	}
+
+	options := make([]huh.Option[int], len(failed))
+	for i, f := range failed {
+		options[i] = huh.NewOption(fmt.Sprintf("%d. %s  %s  %s", i+1, f.IssueKey, timeparse.Format(f.TimeSeconds), f.Description), i)
+	}
+
+	idx := 0
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[int]().
+				Title("Какую строку исправить?").
+				Options(options...).
+				Value(&idx),
+		),
+	)
+	if err := form.Run(); err != nil {
+		return 0, false
+	}
+	return idx, true
+}
+
+// editSubmission lets the user fix the key, time or comment of a row and
+// checks the new key in Jira.
+func (r *Runner) editSubmission(ctx context.Context, sub *submission) {
+	key := sub.IssueKey
+	timeSpent := timeparse.Format(sub.TimeSeconds)
+	comment := sub.Description
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Задача").
+				Value(&key).
+				Validate(func(s string) error {
+					if strings.TrimSpace(s) == "" {
+						return fmt.Errorf("укажите ключ задачи")
+					}
+					return nil
+				}),
+			huh.NewInput().
+				Title("Время").
+				Value(&timeSpent).
+				Validate(func(s string) error {
+					if timeparse.Parse(s) <= 0 {
+						return fmt.Errorf("укажите время, например 2h 30m")
+					}
+					return nil
+				}),
+			huh.NewText().
+				Title("Комментарий").
+				Value(&comment),
+		),
+	)
+	if err := form.Run(); err != nil {
+		return
+	}
+
+	sub.IssueKey = strings.ToUpper(strings.TrimSpace(key))
+	sub.TimeSeconds = timeparse.Parse(timeSpent)
+	sub.Description = comment
+
+	if check := r.checkRows(ctx, []gemini.ParsedWorkLog{sub.ParsedWorkLog}, nil)[0]; check.note != "" {
+		pterm.Warning.Printfln("%s: %s", sub.IssueKey, check.note)
+	}
+}
+
+// RunRetry resubmits worklogs that failed earlier and were kept (sj retry).
+// Rows already logged, e.g. by an attempt whose response was lost, are skipped.
+func (r *Runner) RunRetry(ctx context.Context) error {
+	ui.PrintWelcome()
+
+	items, err := r.failures.Items()
+	if err != nil {
+		ui.PrintError("Не удалось прочитать неотправленные ворклоги: " + err.Error())
+		return err
+	}
+	if len(items) == 0 {
+		ui.PrintStatus("Неотправленных ворклогов нет.")
+		return nil
+	}
+
+	ui.PrintFailedWorklogs(pendingRows(items))
+
+	if !ui.ConfirmYesNo("Повторить отправку?") {
+		ui.PrintCancelled()
+		return nil
+	}
+
+	// A row may have been logged after all, e.g. when the response was lost.
+	submissions := r.skipLogged(ctx, items)
+
+	failed := r.postSubmissions(ctx, submissions)
+	ui.PrintSubmissionReport(len(submissions)-len(failed), len(submissions))
+	failed = r.queueOffline(failed)
+	// Persist right away so that sent rows are not sent again if sj is killed.
+	if err := r.failures.Save(pendingOf(failed)); err != nil {
+		return err
+	}
+
+	remaining := r.recoverFailures(ctx, failed)
+	if err := r.failures.Save(pendingOf(remaining)); err != nil {
+		return err
+	}
+	if len(remaining) > 0 {
+		pterm.Warning.Printfln("Не отправлено: %d. Повторить позже: sj retry", len(remaining))
+		return nil
+	}
+
+	ui.PrintFarewell()
+	return nil
+}
+
+func submissionsOf(failed []failedSubmission) []submission {
+	subs := make([]submission, len(failed))
+	for i, f := range failed {
+		subs[i] = f.submission
+	}
+	return subs
+}
+
+func failedRows(failed []failedSubmission) []ui.FailedWorklog {
+	rows := make([]ui.FailedWorklog, len(failed))
+	for i, f := range failed {
+		rows[i] = ui.FailedWorklog{
+			IssueKey:    f.IssueKey,
+			Date:        f.Started.Format("2006-01-02"),
+			TimeSeconds: f.TimeSeconds,
+			Description: f.Description,
+			Error:       describeError(f.err),
+		}
+	}
+	return rows
+}
+
+func pendingRows(items []journal.Pending) []ui.FailedWorklog {
+	rows := make([]ui.FailedWorklog, len(items))
+	for i, p := range items {
+		rows[i] = ui.FailedWorklog{
+			IssueKey:    p.IssueKey,
+			Date:        p.Started.Format("2006-01-02"),
+			TimeSeconds: p.TimeSpentSeconds,
+			Description: p.Comment,
+			Error:       p.Error,
+		}
+	}
+	return rows
+}
+
+func pendingOf(failed []failedSubmission) []journal.Pending {
+	items := make([]journal.Pending, len(failed))
+	for i, f := range failed {
+		items[i] = journal.Pending{
+			IssueKey:         f.IssueKey,
+			TimeSpentSeconds: f.TimeSeconds,
+			Comment:          f.Description,
+			Started:          f.Started,
+			Error:            describeError(f.err),
+			Visibility:       f.Visibility,
+		}
+		if f.Replaces != nil {
+			items[i].ReplacesIssueKey = f.Replaces.IssueKey
+			items[i].ReplacesWorklogID = f.Replaces.ID
+		}
+	}
+	return items
+}
+
+func submissionOf(p journal.Pending) submission {
+	sub := submission{
+		ParsedWorkLog: gemini.ParsedWorkLog{
+			IssueKey:    p.IssueKey,
+			TimeSeconds: p.TimeSpentSeconds,
+			Description: p.Comment,
+		},
+		Started:    p.Started,
+		Visibility: p.Visibility,
+	}
+	if p.ReplacesWorklogID != "" {
+		sub.Replaces = &jira.Worklog{ID: p.ReplacesWorklogID, IssueKey: p.ReplacesIssueKey}
+	}
+	return sub
+}
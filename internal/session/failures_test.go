@@ -0,0 +1,30 @@
+package session
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go-secretary/internal/gemini"
+	"go-secretary/internal/jira"
+)
+
+func TestPendingRoundTrip(t *testing.T) {
+	started := time.Date(2025, 3, 4, 9, 0, 0, 0, time.UTC)
+	sub := submission{
+		ParsedWorkLog: gemini.ParsedWorkLog{IssueKey: "PROJ-1", TimeSeconds: 3600, Description: "review"},
+		Started:       started,
+		Replaces:      &jira.Worklog{ID: "42", IssueKey: "PROJ-1"},
+	}
+
+	items := pendingOf([]failedSubmission{{submission: sub, err: errors.New("boom")}})
+	if len(items) != 1 || items[0].Error == "" {
+		t.Fatalf("got %+v", items)
+	}
+
+	got := submissionOf(items[0])
+	if got.ParsedWorkLog != sub.ParsedWorkLog || !got.Started.Equal(started) ||
+		got.Replaces == nil || got.Replaces.ID != "42" {
+		t.Errorf("got %+v, want %+v", got, sub)
+	}
+}
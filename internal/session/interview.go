@@ -2,12 +2,16 @@ package session
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"go-secretary/internal/config"
 	"go-secretary/internal/gemini"
 	"go-secretary/internal/jira"
+	"go-secretary/internal/journal"
 	"go-secretary/internal/ui"
 
 	"github.com/charmbracelet/huh"
@@ -23,26 +27,35 @@ const (
 )
 
 type Runner struct {
-	jira   *jira.Client
-	gemini *gemini.Assistant
+	jira     *jira.Client
+	sink     WorklogSink
+	gemini   *gemini.Assistant
+	journal  *journal.Journal
+	failures *journal.Queue
+	outbox   *journal.Queue
 }
 
-func NewRunner(jiraClient *jira.Client, geminiAssistant *gemini.Assistant) *Runner {
+func NewRunner(jiraClient *jira.Client, sink WorklogSink, geminiAssistant *gemini.Assistant) *Runner {
 	return &Runner{
-		jira:   jiraClient,
-		gemini: geminiAssistant,
+		jira:     jiraClient,
+		sink:     sink,
+		gemini:   geminiAssistant,
+		journal:  journal.New(filepath.Join(config.Dir(), "journal.json")),
+		failures: journal.NewQueue(filepath.Join(config.Dir(), "failed.json")),
+		outbox:   journal.NewQueue(filepath.Join(config.Dir(), "outbox.json")),
 	}
 }
 
 func (r *Runner) Run(ctx context.Context) error {
 	ui.PrintWelcome()
+	r.noticeOutbox()
 
 	// Fetch user's own issues to display as a reminder
 	spinner, _ := pterm.DefaultSpinner.Start("Получаю твои задачи из Jira...")
 	myIssues, err := r.jira.GetMyIssues(ctx)
 	spinner.Stop()
 	if err != nil {
-		ui.PrintError("Ошибка при получении задач из Jira: " + err.Error())
+		ui.PrintError("Ошибка при получении задач из Jira: " + describeError(err))
 		return err
 	}
 
@@ -55,7 +68,7 @@ func (r *Runner) Run(ctx context.Context) error {
 	allIssues, err := r.jira.GetAllIssues(ctx)
 	spinner.Stop()
 	if err != nil {
-		ui.PrintError("Ошибка при получении задач из Jira: " + err.Error())
+		ui.PrintError("Ошибка при получении задач из Jira: " + describeError(err))
 		return err
 	}
 
@@ -65,13 +78,26 @@ func (r *Runner) Run(ctx context.Context) error {
 	}
 
 	// Check today's already logged time
+	today := r.jira.Today(ctx)
 	spinner, _ = pterm.DefaultSpinner.Start("Проверяю ворклоги за сегодня...")
-	loggedSeconds, err := r.jira.GetTodayLoggedSeconds(ctx)
+	loggedByDay, err := r.sink.GetLoggedSecondsForDateRange(ctx, today, today)
 	spinner.Stop()
+	if err = warnPartialWorklogs(err); err != nil {
+		ui.PrintError("Ошибка при получении ворклогов: " + describeError(err))
+		return err
+	}
+	loggedSeconds := loggedByDay[today]
+
+	requiredByDay, err := r.sink.GetRequiredSecondsForDateRange(ctx, today, today)
 	if err != nil {
-		ui.PrintError("Ошибка при получении ворклогов: " + err.Error())
+		ui.PrintError("Ошибка при получении рабочего графика: " + describeError(err))
 		return err
 	}
+	requiredSeconds := requiredByDay[today]
+	if requiredSeconds == 0 {
+		// Logging on a day off: assume a regular workday rather than zero.
+		requiredSeconds = jira.WorkdaySeconds
+	}
 
 	if loggedSeconds > 0 {
 		h := loggedSeconds / 3600
@@ -83,7 +109,7 @@ func (r *Runner) Run(ctx context.Context) error {
 	ui.PrintStatus("Расскажи AI-ассистенту, чем ты сегодня занимался...")
 	time.Sleep(1 * time.Second)
 
-	return r.runConversation(ctx, allIssues, loggedSeconds, "")
+	return r.runConversation(ctx, allIssues, loggedSeconds, requiredSeconds, "")
 }
 
 func (r *Runner) RunPeriod(ctx context.Context) error {
@@ -98,10 +124,16 @@ func (r *Runner) RunPeriod(ctx context.Context) error {
 
 	// Get logged seconds for the period
 	spinner, _ := pterm.DefaultSpinner.Start("Проверяю ворклоги за период...")
-	loggedByDay, err := r.jira.GetLoggedSecondsForDateRange(ctx, startDate, endDate)
+	loggedByDay, err := r.sink.GetLoggedSecondsForDateRange(ctx, startDate, endDate)
 	spinner.Stop()
+	if err = warnPartialWorklogs(err); err != nil {
+		ui.PrintError("Ошибка при получении ворклогов: " + describeError(err))
+		return err
+	}
+
+	requiredByDay, err := r.sink.GetRequiredSecondsForDateRange(ctx, startDate, endDate)
 	if err != nil {
-		ui.PrintError("Ошибка при получении ворклогов: " + err.Error())
+		ui.PrintError("Ошибка при получении рабочего графика: " + describeError(err))
 		return err
 	}
 
@@ -112,20 +144,21 @@ func (r *Runner) RunPeriod(ctx context.Context) error {
 	var days []ui.DayStatus
 	var unfilledDays []ui.DayStatus
 	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
-		wd := d.Weekday()
-		if wd == time.Saturday || wd == time.Sunday {
+		dateStr := d.Format("2006-01-02")
+		required := requiredByDay[dateStr]
+		if required == 0 {
 			continue
 		}
 
-		dateStr := d.Format("2006-01-02")
 		logged := loggedByDay[dateStr]
-		filled := logged >= 8*3600
+		filled := logged >= required
 
 		ds := ui.DayStatus{
-			Date:          dateStr,
-			Weekday:       russianWeekday(wd),
-			LoggedSeconds: logged,
-			Filled:        filled,
+			Date:            dateStr,
+			Weekday:         russianWeekday(d.Weekday()),
+			LoggedSeconds:   logged,
+			RequiredSeconds: required,
+			Filled:          filled,
 		}
 		days = append(days, ds)
 		if !filled {
@@ -149,7 +182,7 @@ func (r *Runner) RunPeriod(ctx context.Context) error {
 	myIssues, err := r.jira.GetMyIssues(ctx)
 	spinner.Stop()
 	if err != nil {
-		ui.PrintError("Ошибка при получении задач из Jira: " + err.Error())
+		ui.PrintError("Ошибка при получении задач из Jira: " + describeError(err))
 		return err
 	}
 
@@ -161,7 +194,7 @@ func (r *Runner) RunPeriod(ctx context.Context) error {
 	allIssues, err := r.jira.GetAllIssues(ctx)
 	spinner.Stop()
 	if err != nil {
-		ui.PrintError("Ошибка при получении задач из Jira: " + err.Error())
+		ui.PrintError("Ошибка при получении задач из Jira: " + describeError(err))
 		return err
 	}
 
@@ -185,7 +218,7 @@ func (r *Runner) RunPeriod(ctx context.Context) error {
 		ui.PrintStatus(fmt.Sprintf("Расскажи AI-ассистенту, чем ты занимался %s...", day.Date))
 		time.Sleep(500 * time.Millisecond)
 
-		if err := r.runConversation(ctx, allIssues, day.LoggedSeconds, day.Date); err != nil {
+		if err := r.runConversation(ctx, allIssues, day.LoggedSeconds, day.RequiredSeconds, day.Date); err != nil {
 			return err
 		}
 	}
@@ -198,9 +231,9 @@ func (r *Runner) RunPeriod(ctx context.Context) error {
 // runConversation runs the AI conversation loop for a single day.
 // If date is empty, worklogs are logged with current time (today mode).
 // If date is set, worklogs are logged with that specific date.
-func (r *Runner) runConversation(ctx context.Context, allIssues []jira.Issue, loggedSeconds int, date string) error {
+func (r *Runner) runConversation(ctx context.Context, allIssues []jira.Issue, loggedSeconds, requiredSeconds int, date string) error {
 startConversation:
-	response, err := r.gemini.StartConversation(ctx, allIssues, loggedSeconds, date)
+	response, err := r.gemini.StartConversation(ctx, allIssues, loggedSeconds, requiredSeconds, date)
 	if err != nil {
 		ui.PrintError("Ошибка при общении с Gemini: " + err.Error())
 		return err
@@ -211,7 +244,7 @@ startConversation:
 	for turn := 0; turn < maxTurns; turn++ {
 		workLogs := r.gemini.ExtractWorkLogs(response)
 		if workLogs != nil {
-			return r.handleSubmissionForDate(ctx, workLogs, date)
+			return r.handleSubmissionForDate(ctx, workLogs, allIssues, date)
 		}
 
 		userInput := ui.ReadInput("Ты: ")
@@ -226,7 +259,7 @@ startConversation:
 
 		// Check for slash commands
 		if cmd, ok := ui.ParseCommand(userInput); ok {
-			action := r.executeCommand(cmd)
+			action := r.executeCommand(ctx, cmd)
 			switch action {
 			case actionExit:
 				ui.PrintFarewell()
@@ -265,10 +298,10 @@ startConversation:
 		return nil
 	}
 
-	return r.handleSubmissionForDate(ctx, workLogs, date)
+	return r.handleSubmissionForDate(ctx, workLogs, allIssues, date)
 }
 
-func (r *Runner) executeCommand(cmd ui.Command) commandAction {
+func (r *Runner) executeCommand(ctx context.Context, cmd ui.Command) commandAction {
 	switch cmd.Name {
 	case "/help":
 		ui.PrintCommands()
@@ -280,15 +313,27 @@ func (r *Runner) executeCommand(cmd ui.Command) commandAction {
 		return actionContinue
 	case "/model":
 		return r.handleModelSwitch()
+	case "/undo":
+		if err := r.undo(ctx); err != nil {
+			ui.PrintError("Ошибка при отмене: " + describeError(err))
+		}
+		return actionContinue
 	case "/config":
 		cfg, err := config.RunSetup()
 		if err != nil {
 			ui.PrintError("Ошибка настройки: " + err.Error())
 			return actionContinue
 		}
-		r.gemini.SetModel(cfg.GeminiModel)
+		geminiCfg, err := cfg.GeminiConfig()
+		if err == nil {
+			err = r.gemini.Reconnect(ctx, geminiCfg)
+		}
+		if err != nil {
+			ui.PrintError("Ошибка подключения к Gemini: " + err.Error())
+			return actionContinue
+		}
 		ui.PrintStatus("Настройки обновлены.")
-		return actionContinue
+		return actionRestart
 	default:
 		ui.PrintError("Неизвестная команда: " + cmd.Name)
 		ui.PrintCommands()
@@ -317,35 +362,67 @@ func (r *Runner) handleModelSwitch() commandAction {
 	return actionRestart
 }
 
-func (r *Runner) handleSubmissionForDate(ctx context.Context, workLogs []gemini.ParsedWorkLog, date string) error {
-	ui.PrintSummary(workLogs)
+func (r *Runner) handleSubmissionForDate(ctx context.Context, workLogs []gemini.ParsedWorkLog, issues []jira.Issue, date string) error {
+	workLogs = r.validateWorkLogs(ctx, workLogs, issues)
+	if len(workLogs) == 0 {
+		ui.PrintCancelled()
+		return nil
+	}
+
+	submissions := r.resolveDuplicates(ctx, workLogs, date)
+	if len(submissions) == 0 {
+		ui.PrintStatus("Отправлять нечего: всё уже залогировано.")
+		return nil
+	}
 
-	if !ui.ConfirmYesNo("Отправить эти данные в Jira?") {
+	if !r.confirmSubmission(submissions) {
 		ui.PrintCancelled()
 		return nil
 	}
 
-	var started time.Time
-	if date != "" {
-		started, _ = time.Parse("2006-01-02", date)
+	started, err := r.jira.WorkdayStart(ctx, date)
+	if err != nil {
+		ui.PrintError("Некорректная дата: " + err.Error())
+		return err
+	}
+	for i := range submissions {
+		submissions[i].Started = started
 	}
 
-	pterm.Println()
-	for _, log := range workLogs {
-		spinner, _ := pterm.DefaultSpinner.Start("Логирую " + log.IssueKey + "...")
-		err := r.jira.LogWork(ctx, log.IssueKey, log.TimeSeconds, log.Description, started)
-		spinner.Stop()
-		ui.PrintLogResult(log.IssueKey, err == nil)
-		if err != nil {
-			ui.PrintError("  " + err.Error())
+	// The model may have been switched with /model during the session.
+	tag := r.jira.WorklogTag()
+	tag.Model = r.gemini.Model()
+	r.jira.SetWorklogTag(tag)
+
+	failed := r.postSubmissions(ctx, submissions)
+	ui.PrintSubmissionReport(len(submissions)-len(failed), len(submissions))
+	failed = r.queueOffline(failed)
+	remaining := r.recoverFailures(ctx, failed)
+	if len(remaining) > 0 {
+		if err := r.failures.Add(pendingOf(remaining)...); err != nil {
+			ui.PrintError("Не удалось сохранить неотправленные ворклоги: " + err.Error())
+			return err
 		}
-		time.Sleep(300 * time.Millisecond)
+		pterm.Warning.Printfln("Не отправлено: %d. Повторить позже: sj retry", len(remaining))
+		return nil
 	}
 
 	ui.PrintFarewell()
 	return nil
 }
 
+// warnPartialWorklogs prints which issues could not be read and swallows the
+// error, since the logged time of the remaining issues is still usable.
+func warnPartialWorklogs(err error) error {
+	var issueErrs jira.IssueErrors
+	if !errors.As(err, &issueErrs) {
+		return err
+	}
+	ui.PrintError(fmt.Sprintf("Не удалось прочитать ворклоги задач: %s. Залогированное время может быть занижено.",
+		strings.Join(issueErrs.Keys(), ", ")))
+	return nil
+}
+
 func russianWeekday(wd time.Weekday) string {
 	switch wd {
 	case time.Monday:
@@ -0,0 +1,98 @@
+package session
+
+import (
+	"context"
+
+	"go-secretary/internal/ui"
+
+	"github.com/pterm/pterm"
+)
+
+// queueOffline moves rows that failed because the server was unreachable to
+// the outbox and returns the rest.
+func (r *Runner) queueOffline(failed []failedSubmission) []failedSubmission {
+	offline, rest := splitOffline(failed)
+	if len(offline) == 0 {
+		return failed
+	}
+	if err := r.outbox.Add(pendingOf(offline)...); err != nil {
+		ui.PrintError("Не удалось поставить ворклоги в очередь: " + err.Error())
+		return failed
+	}
+	pterm.Warning.Printfln("Сервер недоступен: ворклогов в очереди: %d. Отправить, когда связь появится: sj sync", len(offline))
+	return rest
+}
+
+// noticeOutbox reminds about worklogs waiting in the outbox.
+func (r *Runner) noticeOutbox() {
+	items, err := r.outbox.Items()
+	if err != nil || len(items) == 0 {
+		return
+	}
+	pterm.Warning.Printfln("В очереди %d ворклог(ов), не отправленных из-за недоступности сервера. Отправить: sj sync", len(items))
+	pterm.Println()
+}
+
+// RunSync submits the worklogs queued while the server was unreachable (sj sync).
+// Rows already logged, e.g. by an attempt whose response was lost, are skipped.
+func (r *Runner) RunSync(ctx context.Context) error {
+	ui.PrintWelcome()
+
+	items, err := r.outbox.Items()
+	if err != nil {
+		ui.PrintError("Не удалось прочитать очередь: " + err.Error())
+		return err
+	}
+	if len(items) == 0 {
+		ui.PrintStatus("Очередь пуста.")
+		return nil
+	}
+
+	ui.PrintFailedWorklogs(pendingRows(items))
+	if !ui.ConfirmYesNo("Отправить ворклоги из очереди?") {
+		ui.PrintCancelled()
+		return nil
+	}
+
+	submissions := r.skipLogged(ctx, items)
+
+	failed := r.postSubmissions(ctx, submissions)
+	ui.PrintSubmissionReport(len(submissions)-len(failed), len(submissions))
+
+	// Rows that still cannot reach the server stay queued; rejected ones move
+	// on to the usual failure handling.
+	offline, rest := splitOffline(failed)
+	if err := r.outbox.Save(pendingOf(offline)); err != nil {
+		ui.PrintError("Не удалось обновить очередь: " + err.Error())
+		return err
+	}
+	if len(offline) > 0 {
+		pterm.Warning.Printfln("Сервер всё ещё недоступен: в очереди осталось %d.", len(offline))
+	}
+
+	remaining := r.recoverFailures(ctx, rest)
+	if len(remaining) > 0 {
+		if err := r.failures.Add(pendingOf(remaining)...); err != nil {
+			ui.PrintError("Не удалось сохранить неотправленные ворклоги: " + err.Error())
+			return err
+		}
+		pterm.Warning.Printfln("Не отправлено: %d. Повторить позже: sj retry", len(remaining))
+		return nil
+	}
+
+	if len(offline) == 0 {
+		ui.PrintFarewell()
+	}
+	return nil
+}
+
+func splitOffline(failed []failedSubmission) (offline, rest []failedSubmission) {
+	for _, f := range failed {
+		if isOffline(f.err) {
+			offline = append(offline, f)
+		} else {
+			rest = append(rest, f)
+		}
+	}
+	return offline, rest
+}
@@ -0,0 +1,31 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"testing"
+
+	"go-secretary/internal/jira"
+)
+
+func TestSplitOffline(t *testing.T) {
+	dialErr := &url.Error{Op: "Post", URL: "https://jira", Err: &net.OpError{Op: "dial", Err: errors.New("no route to host")}}
+	canceled := &url.Error{Op: "Post", URL: "https://jira", Err: context.Canceled}
+
+	failed := []failedSubmission{
+		{err: dialErr},
+		{err: &jira.APIError{StatusCode: 400}},
+		{err: canceled},
+		{err: errors.New("tempo returned 400: bad request")},
+	}
+
+	offline, rest := splitOffline(failed)
+	if len(offline) != 1 || offline[0].err != dialErr {
+		t.Errorf("offline = %+v", offline)
+	}
+	if len(rest) != 3 {
+		t.Errorf("rest = %+v", rest)
+	}
+}
@@ -0,0 +1,26 @@
+package session
+
+import (
+	"context"
+	"time"
+
+	"go-secretary/internal/jira"
+)
+
+// WorklogSink is the backend confirmed worklogs are written to and logged time
+// is read from: plain Jira or Tempo Timesheets. Worklogs it lists carry IDs
+// of that backend, so they can be passed back to UpdateWorklog and DeleteWorklog.
+type WorklogSink interface {
+	LogWork(ctx context.Context, issueKey string, timeSpentSeconds int, description string, started time.Time) (string, error)
+	UpdateWorklog(ctx context.Context, issueKey, worklogID string, timeSpentSeconds int, comment string) error
+	DeleteWorklog(ctx context.Context, issueKey, worklogID string) error
+	ListMyWorklogs(ctx context.Context, startDate, endDate string) ([]jira.Worklog, error)
+	GetLoggedSecondsForDateRange(ctx context.Context, startDate, endDate string) (map[string]int, error)
+	GetRequiredSecondsForDateRange(ctx context.Context, startDate, endDate string) (map[string]int, error)
+}
+
+// visibilitySink is implemented by sinks that can restrict who sees a worklog.
+// Tempo worklogs follow the visibility of the Jira issue, so only Jira has it.
+type visibilitySink interface {
+	LogWorkWithVisibility(ctx context.Context, issueKey string, timeSpentSeconds int, description string, started time.Time, visibility *jira.Visibility) (string, error)
+}
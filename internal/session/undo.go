@@ -0,0 +1,250 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"go-secretary/internal/jira"
+	"go-secretary/internal/journal"
+	"go-secretary/internal/timeparse"
+	"go-secretary/internal/ui"
+
+	"github.com/charmbracelet/huh"
+	"github.com/pterm/pterm"
+)
+
+// RunUndo deletes the worklogs created in a previous session (sj undo).
+func (r *Runner) RunUndo(ctx context.Context) error {
+	ui.PrintWelcome()
+	if err := r.undo(ctx); err != nil {
+		ui.PrintError("Ошибка при отмене: " + describeError(err))
+		return err
+	}
+	return nil
+}
+
+// recordWorklog adds a created worklog to the session journal, along with the
+// worklog it replaced, if any. A journal failure does not affect the worklog
+// itself, so it is only reported.
+func (r *Runner) recordWorklog(sub submission, worklogID string, replaced *jira.Worklog) {
+	entry := journal.Entry{
+		IssueKey:         sub.IssueKey,
+		WorklogID:        worklogID,
+		TimeSpentSeconds: sub.TimeSeconds,
+		Comment:          sub.Description,
+		Started:          sub.Started,
+	}
+	if replaced != nil {
+		entry.Replaced = &journal.Replaced{
+			IssueKey:         worklogIssue(*replaced),
+			TimeSpentSeconds: replaced.TimeSpentSeconds,
+			Comment:          replaced.Comment,
+			Started:          replaced.Started,
+		}
+	}
+	if err := r.journal.Record(r.jira.WorklogTag().Session, r.backend(), entry); err != nil {
+		ui.PrintError("  не удалось записать ворклог в журнал: " + err.Error())
+	}
+}
+
+// recoverDays is how far back sj undo looks for tagged Jira worklogs of
+// sessions that are missing from the journal.
+const recoverDays = 14
+
+// undo lets the user pick a session from the journal, most recent first, and
+// deletes its worklogs after confirmation. Worklogs the session replaced are
+// logged again. With the Jira backend, sessions
+// found only by their sj.session tag are offered as well, e.g. after the
+// journal was lost or sj ran on another machine.
+func (r *Runner) undo(ctx context.Context) error {
+	sessions, err := r.journal.Sessions()
+	if err != nil {
+		return err
+	}
+	recovered := r.recoverSessions(ctx, sessions)
+	sessions = append(sessions, recovered...)
+	sort.SliceStable(sessions, func(i, j int) bool { return sessions[i].CreatedAt.After(sessions[j].CreatedAt) })
+	if len(sessions) == 0 {
+		ui.PrintStatus("Журнал пуст: отменять нечего.")
+		return nil
+	}
+
+	fromJira := make(map[string]bool, len(recovered))
+	for _, s := range recovered {
+		fromJira[s.ID] = true
+	}
+	s, ok := selectSession(sessions, fromJira)
+	if !ok {
+		ui.PrintCancelled()
+		return nil
+	}
+	if s.Backend != r.backend() {
+		return fmt.Errorf("сессия записана в %s, а сейчас настроен %s", s.Backend, r.backend())
+	}
+
+	worklogs := make([]jira.Worklog, len(s.Entries))
+	for i, e := range s.Entries {
+		worklogs[i] = jira.Worklog{
+			ID:               e.WorklogID,
+			IssueKey:         e.IssueKey,
+			Comment:          e.Comment,
+			Started:          e.Started,
+			TimeSpentSeconds: e.TimeSpentSeconds,
+		}
+	}
+	ui.PrintWorklogsTable(worklogs)
+
+	if n := countReplaced(s.Entries); n > 0 {
+		pterm.Warning.Printfln("Ворклоги сессии заменили существующие (%d): они будут созданы заново.", n)
+	}
+	if !ui.ConfirmYesNo(fmt.Sprintf("Удалить эти ворклоги (%d)?", len(worklogs))) {
+		ui.PrintCancelled()
+		return nil
+	}
+
+	var deleted []string
+	for i, wl := range worklogs {
+		err := r.sink.DeleteWorklog(ctx, wl.IssueKey, wl.ID)
+		if jira.IsNotFound(err) {
+			// Already deleted by hand.
+			err = nil
+		}
+		ui.PrintLogResult(wl.IssueKey, err == nil)
+		if err != nil {
+			ui.PrintError("  " + describeError(err))
+			continue
+		}
+		deleted = append(deleted, wl.ID)
+		if old := s.Entries[i].Replaced; old != nil {
+			r.restoreReplaced(ctx, *old)
+		}
+	}
+
+	if err := r.journal.Forget(s.ID, deleted); err != nil {
+		return err
+	}
+	if len(deleted) == len(worklogs) {
+		ui.PrintStatus("Сессия отменена.")
+	} else {
+		pterm.Warning.Printfln("Удалено %d из %d ворклогов; остальные остались в журнале.", len(deleted), len(worklogs))
+	}
+	return nil
+}
+
+// restoreReplaced logs a replaced worklog again. If that fails, its details
+// are printed so it can be logged by hand, since the journal forgets it.
+func (r *Runner) restoreReplaced(ctx context.Context, old journal.Replaced) {
+	_, err := r.sink.LogWork(ctx, old.IssueKey, old.TimeSpentSeconds, old.Comment, old.Started)
+	if err == nil {
+		ui.PrintStatus(fmt.Sprintf("  восстановлен прежний ворклог %s %s", old.IssueKey, timeparse.Format(old.TimeSpentSeconds)))
+		return
+	}
+	ui.PrintError(fmt.Sprintf("  не удалось восстановить прежний ворклог %s %s (%s, «%s»): %s",
+		old.IssueKey, timeparse.Format(old.TimeSpentSeconds), old.Started.Format("2006-01-02 15:04"), old.Comment, describeError(err)))
+}
+
+func countReplaced(entries []journal.Entry) int {
+	n := 0
+	for _, e := range entries {
+		if e.Replaced != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// recoverSessions lists sj-tagged Jira worklogs of the last recoverDays days
+// that belong to sessions not in the journal. Read errors are only reported.
+func (r *Runner) recoverSessions(ctx context.Context, known []journal.Session) []journal.Session {
+	if r.backend() != "jira" {
+		return nil
+	}
+	today, err := time.Parse("2006-01-02", r.jira.Today(ctx))
+	if err != nil {
+		return nil
+	}
+	from := today.AddDate(0, 0, -recoverDays).Format("2006-01-02")
+
+	spinner, _ := pterm.DefaultSpinner.WithRemoveWhenDone(true).Start("Ищу ворклоги sj в Jira...")
+	tagged, err := r.jira.ListTaggedWorklogs(ctx, from, today.Format("2006-01-02"))
+	spinner.Stop()
+	if err = warnPartialWorklogs(err); err != nil {
+		ui.PrintError("Не удалось найти ворклоги sj в Jira: " + describeError(err))
+	}
+	return taggedSessions(tagged, known)
+}
+
+// taggedSessions groups tagged worklogs into sessions, leaving out sessions
+// that are already known. A session's time is that of its earliest worklog.
+func taggedSessions(worklogs []jira.Worklog, known []journal.Session) []journal.Session {
+	skip := make(map[string]bool, len(known))
+	for _, s := range known {
+		skip[s.ID] = true
+	}
+
+	var sessions []journal.Session
+	index := make(map[string]int)
+	for _, wl := range worklogs {
+		if wl.Tag == nil || skip[wl.Tag.Session] {
+			continue
+		}
+		i, ok := index[wl.Tag.Session]
+		if !ok {
+			i = len(sessions)
+			index[wl.Tag.Session] = i
+			sessions = append(sessions, journal.Session{ID: wl.Tag.Session, Backend: "jira", CreatedAt: wl.Started})
+		}
+		s := &sessions[i]
+		if wl.Started.Before(s.CreatedAt) {
+			s.CreatedAt = wl.Started
+		}
+		s.Entries = append(s.Entries, journal.Entry{
+			IssueKey:         wl.IssueKey,
+			WorklogID:        wl.ID,
+			TimeSpentSeconds: wl.TimeSpentSeconds,
+			Comment:          wl.Comment,
+			Started:          wl.Started,
+		})
+	}
+	return sessions
+}
+
+func selectSession(sessions []journal.Session, fromJira map[string]bool) (journal.Session, bool) {
+	options := make([]huh.Option[int], 0, len(sessions)+1)
+	for i, s := range sessions {
+		label := fmt.Sprintf("%s  %d ворклог(ов), %s",
+			s.CreatedAt.Local().Format("2006-01-02 15:04"), len(s.Entries), timeparse.Format(s.TotalSeconds()))
+		if i == 0 {
+			label += "  (последняя)"
+		}
+		if fromJira[s.ID] {
+			label += "  (найдена по метке в Jira)"
+		}
+		options = append(options, huh.NewOption(label, i))
+	}
+	options = append(options, huh.NewOption("Отмена", -1))
+
+	idx := 0
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[int]().
+				Title("Какую сессию отменить?").
+				Options(options...).
+				Value(&idx),
+		),
+	)
+	if err := form.Run(); err != nil || idx < 0 {
+		return journal.Session{}, false
+	}
+	return sessions[idx], true
+}
+
+// backend names the sink worklog IDs belong to.
+func (r *Runner) backend() string {
+	if _, ok := r.sink.(*jira.Client); ok {
+		return "jira"
+	}
+	return "tempo"
+}
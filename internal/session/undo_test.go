@@ -0,0 +1,29 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"go-secretary/internal/jira"
+	"go-secretary/internal/journal"
+)
+
+func TestTaggedSessions(t *testing.T) {
+	at := func(hour int) time.Time { return time.Date(2025, 3, 3, hour, 0, 0, 0, time.UTC) }
+	worklogs := []jira.Worklog{
+		{ID: "1", IssueKey: "A-1", Started: at(11), TimeSpentSeconds: 3600, Tag: &jira.WorklogTag{Session: "s2"}},
+		{ID: "2", IssueKey: "A-2", Started: at(9), TimeSpentSeconds: 1800, Tag: &jira.WorklogTag{Session: "s2"}},
+		{ID: "3", IssueKey: "A-1", Started: at(9), TimeSpentSeconds: 600, Tag: &jira.WorklogTag{Session: "s1"}},
+		{ID: "4", IssueKey: "A-3", Started: at(10), TimeSpentSeconds: 600},
+	}
+	known := []journal.Session{{ID: "s1"}}
+
+	got := taggedSessions(worklogs, known)
+	if len(got) != 1 {
+		t.Fatalf("got %d sessions, want 1: %+v", len(got), got)
+	}
+	s := got[0]
+	if s.ID != "s2" || s.Backend != "jira" || !s.CreatedAt.Equal(at(9)) || len(s.Entries) != 2 || s.TotalSeconds() != 5400 {
+		t.Errorf("unexpected session %+v", s)
+	}
+}
@@ -0,0 +1,174 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go-secretary/internal/gemini"
+	"go-secretary/internal/jira"
+	"go-secretary/internal/timeparse"
+	"go-secretary/internal/ui"
+
+	"github.com/pterm/pterm"
+)
+
+// maxKeyDistance is how many typos a key may have to be suggested as a fix.
+const maxKeyDistance = 2
+
+// rowCheck is the pre-submit verdict for one row of the summary.
+type rowCheck struct {
+	note       string
+	suggestion string
+	problem    jira.WorklogProblem
+}
+
+// validateWorkLogs checks every issue key before the summary is shown and
+// offers fixes for rows that would fail: typo corrections, moved issues,
+// or dropping the row. It returns the rows to submit.
+func (r *Runner) validateWorkLogs(ctx context.Context, workLogs []gemini.ParsedWorkLog, issues []jira.Issue) []gemini.ParsedWorkLog {
+	spinner, _ := pterm.DefaultSpinner.WithRemoveWhenDone(true).Start("Проверяю задачи в Jira...")
+	checks := r.checkRows(ctx, workLogs, issues)
+	spinner.Stop()
+
+	ui.PrintSummary(workLogs, rowNotes(checks))
+
+	changed := false
+	var result []gemini.ParsedWorkLog
+	for i, log := range workLogs {
+		check := checks[i]
+		switch {
+		case check.suggestion != "":
+			if ui.ConfirmYesNo(fmt.Sprintf("Заменить %s на %s?", log.IssueKey, check.suggestion)) {
+				log.IssueKey = check.suggestion
+				changed = true
+				break
+			}
+			// Without the fix the row would fail just the same.
+			if check.problem != jira.ProblemNone && ui.ConfirmYesNo(fmt.Sprintf("Убрать %s из отправки?", log.IssueKey)) {
+				changed = true
+				continue
+			}
+		case check.problem == jira.ProblemClosed:
+			if !ui.ConfirmYesNo(fmt.Sprintf("%s закрыта. Всё равно логировать?", log.IssueKey)) {
+				changed = true
+				continue
+			}
+		case check.problem != jira.ProblemNone:
+			if ui.ConfirmYesNo(fmt.Sprintf("Убрать %s из отправки?", log.IssueKey)) {
+				changed = true
+				continue
+			}
+		}
+		result = append(result, log)
+	}
+
+	if changed && len(result) > 0 {
+		ui.PrintSummary(result, nil)
+	}
+	return result
+}
+
+func (r *Runner) checkRows(ctx context.Context, workLogs []gemini.ParsedWorkLog, issues []jira.Issue) []rowCheck {
+	byKey := make(map[string]rowCheck)
+	checks := make([]rowCheck, len(workLogs))
+
+	totals := make(map[string]int)
+	for _, log := range workLogs {
+		totals[strings.ToUpper(strings.TrimSpace(log.IssueKey))] += log.TimeSeconds
+	}
+
+	for i, log := range workLogs {
+		key := strings.ToUpper(strings.TrimSpace(log.IssueKey))
+		if check, ok := byKey[key]; ok {
+			checks[i] = check
+			continue
+		}
+
+		var check rowCheck
+		result, err := r.jira.CheckWorklogTarget(ctx, key)
+		switch {
+		case err != nil:
+			check.note = "не удалось проверить: " + describeError(err)
+		case result.Problem == jira.ProblemNotFound:
+			check.problem = result.Problem
+			check.note = "задача не найдена"
+			if s := closestKey(key, issues); s != "" {
+				check.suggestion = s
+				check.note += ", возможно " + s
+			}
+		case result.Issue.Key != key:
+			check.suggestion = result.Issue.Key
+			check.note = "задача перенесена в " + result.Issue.Key
+		case result.Problem == jira.ProblemClosed:
+			check.problem = result.Problem
+			check.note = fmt.Sprintf("задача закрыта (%s)", result.Issue.Status)
+		case result.Problem == jira.ProblemNoPermission:
+			check.problem = result.Problem
+			check.note = "нет права логировать время (WORK_ON_ISSUES)"
+		}
+		if result.Issue != nil && exceedsEstimate(*result.Issue, totals[key]) {
+			note := "больше остатка оценки (" + timeparse.Format(result.Issue.RemainingEstimateSeconds) + ")"
+			if check.note != "" {
+				note = check.note + "; " + note
+			}
+			check.note = note
+		}
+
+		byKey[key] = check
+		checks[i] = check
+	}
+	return checks
+}
+
+// exceedsEstimate reports logging more than is left of the issue's estimate.
+// Issues without an estimate are never over it.
+func exceedsEstimate(issue jira.Issue, seconds int) bool {
+	if issue.OriginalEstimateSeconds == 0 && issue.RemainingEstimateSeconds == 0 {
+		return false
+	}
+	return seconds > issue.RemainingEstimateSeconds
+}
+
+func rowNotes(checks []rowCheck) []string {
+	notes := make([]string, len(checks))
+	for i, c := range checks {
+		notes[i] = c.note
+	}
+	return notes
+}
+
+// closestKey returns the issue key most similar to key, if it is close
+// enough to be a typo (e.g. PORJ-12 for PROJ-12).
+func closestKey(key string, issues []jira.Issue) string {
+	best := ""
+	bestDist := maxKeyDistance + 1
+	for _, issue := range issues {
+		if d := editDistance(key, strings.ToUpper(issue.Key)); d < bestDist {
+			best, bestDist = issue.Key, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
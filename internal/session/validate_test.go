@@ -0,0 +1,52 @@
+package session
+
+import (
+	"testing"
+
+	"go-secretary/internal/jira"
+)
+
+func TestClosestKey(t *testing.T) {
+	issues := []jira.Issue{{Key: "PROJ-12"}, {Key: "PROJ-120"}, {Key: "OPS-7"}}
+
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"PORJ-12", "PROJ-12"},
+		{"PROJ-21", "PROJ-12"},
+		{"OPS-8", "OPS-7"},
+		{"PROJ-1200", "PROJ-120"},
+		{"DOCS-1", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := closestKey(tt.key, issues); got != tt.want {
+				t.Errorf("closestKey(%q) = %q, want %q", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExceedsEstimate(t *testing.T) {
+	tests := []struct {
+		name    string
+		issue   jira.Issue
+		seconds int
+		want    bool
+	}{
+		{"no estimate", jira.Issue{}, 3600, false},
+		{"within remaining", jira.Issue{OriginalEstimateSeconds: 7200, RemainingEstimateSeconds: 3600}, 3600, false},
+		{"over remaining", jira.Issue{OriginalEstimateSeconds: 7200, RemainingEstimateSeconds: 3600}, 5400, true},
+		{"estimate used up", jira.Issue{OriginalEstimateSeconds: 7200}, 60, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exceedsEstimate(tt.issue, tt.seconds); got != tt.want {
+				t.Errorf("exceedsEstimate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package session
+
+import (
+	"fmt"
+	"strings"
+
+	"go-secretary/internal/jira"
+	"go-secretary/internal/timeparse"
+	"go-secretary/internal/ui"
+
+	"github.com/charmbracelet/huh"
+	"github.com/pterm/pterm"
+)
+
+type submitAction int
+
+const (
+	submitSend submitAction = iota
+	submitVisibility
+	submitCancel
+)
+
+// confirmSubmission asks whether to send the rows. With a Jira backend the
+// user may first change who can see individual worklogs.
+func (r *Runner) confirmSubmission(submissions []submission) bool {
+	if _, ok := r.sink.(visibilitySink); !ok {
+		return ui.ConfirmYesNo("Отправить эти данные в Jira?")
+	}
+
+	for {
+		r.printVisibility(submissions)
+
+		action := submitSend
+		form := huh.NewForm(
+			huh.NewGroup(
+				huh.NewSelect[submitAction]().
+					Title("Отправить эти данные в Jira?").
+					Options(
+						huh.NewOption("Отправить", submitSend),
+						huh.NewOption("Изменить видимость ворклога", submitVisibility),
+						huh.NewOption("Отмена", submitCancel),
+					).
+					Value(&action),
+			),
+		)
+		if err := form.Run(); err != nil {
+			return false
+		}
+
+		switch action {
+		case submitSend:
+			return true
+		case submitVisibility:
+			r.editVisibility(submissions)
+		default:
+			return false
+		}
+	}
+}
+
+// effectiveVisibility is the row's override or the project's default.
+func (r *Runner) effectiveVisibility(sub submission) *jira.Visibility {
+	if sub.Visibility != nil {
+		return sub.Visibility
+	}
+	return r.jira.WorklogPolicy(sub.IssueKey).Visibility
+}
+
+// printVisibility lists the rows that will not be visible to everyone.
+func (r *Runner) printVisibility(submissions []submission) {
+	for _, sub := range submissions {
+		if v := r.effectiveVisibility(sub); v.Restricted() {
+			pterm.Println(pterm.Gray(fmt.Sprintf("  %s видно только: %s", sub.IssueKey, describeVisibility(v))))
+		}
+	}
+}
+
+func (r *Runner) editVisibility(submissions []submission) {
+	options := make([]huh.Option[int], len(submissions))
+	for i, sub := range submissions {
+		label := fmt.Sprintf("%d. %s  %s  (%s)", i+1, sub.IssueKey, timeparse.Format(sub.TimeSeconds),
+			describeVisibility(r.effectiveVisibility(sub)))
+		options[i] = huh.NewOption(label, i)
+	}
+
+	idx := 0
+	if len(submissions) > 1 {
+		form := huh.NewForm(
+			huh.NewGroup(
+				huh.NewSelect[int]().
+					Title("Выберите ворклог").
+					Options(options...).
+					Value(&idx),
+			),
+		)
+		if err := form.Run(); err != nil {
+			return
+		}
+	}
+
+	current := r.effectiveVisibility(submissions[idx])
+	var visType, value string
+	if current != nil {
+		visType, value = current.Type, current.Value
+	}
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Кто видит ворклог").
+				Options(
+					huh.NewOption("Все", ""),
+					huh.NewOption("Группа", jira.VisibilityGroup),
+					huh.NewOption("Роль проекта", jira.VisibilityRole),
+				).
+				Value(&visType),
+		).Title(submissions[idx].IssueKey),
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Название группы или роли").
+				Value(&value).
+				Validate(func(s string) error {
+					if strings.TrimSpace(s) == "" {
+						return fmt.Errorf("укажите группу или роль")
+					}
+					return nil
+				}),
+		).WithHideFunc(func() bool { return visType == "" }),
+	)
+	if err := form.Run(); err != nil {
+		return
+	}
+
+	if visType == "" {
+		value = ""
+	}
+	submissions[idx].Visibility = &jira.Visibility{Type: visType, Value: strings.TrimSpace(value)}
+}
+
+func describeVisibility(v *jira.Visibility) string {
+	switch {
+	case !v.Restricted():
+		return "все"
+	case v.Type == jira.VisibilityRole:
+		return "роль " + v.Value
+	default:
+		return "группа " + v.Value
+	}
+}
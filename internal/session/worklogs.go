@@ -0,0 +1,162 @@
+package session
+
+import (
+	"context"
+	"fmt"
+
+	"go-secretary/internal/jira"
+	"go-secretary/internal/timeparse"
+	"go-secretary/internal/ui"
+
+	"github.com/charmbracelet/huh"
+	"github.com/pterm/pterm"
+)
+
+type worklogAction int
+
+const (
+	worklogBack worklogAction = iota
+	worklogEdit
+	worklogDelete
+)
+
+// RunWorklogs shows the user's worklogs for a chosen day and lets them fix
+// time or comment, or delete entries.
+func (r *Runner) RunWorklogs(ctx context.Context) error {
+	ui.PrintWelcome()
+
+	date, err := ui.ReadDate(r.jira.Today(ctx))
+	if err != nil {
+		ui.PrintError("Ошибка при вводе даты: " + err.Error())
+		return err
+	}
+
+	for {
+		spinner, _ := pterm.DefaultSpinner.Start("Загружаю ворклоги за " + date + "...")
+		worklogs, err := r.sink.ListMyWorklogs(ctx, date, date)
+		spinner.Stop()
+		if err = warnPartialWorklogs(err); err != nil {
+			ui.PrintError("Ошибка при получении ворклогов: " + describeError(err))
+			return err
+		}
+
+		if len(worklogs) == 0 {
+			ui.PrintStatus("За " + date + " ворклогов нет.")
+			return nil
+		}
+
+		ui.PrintWorklogsTable(worklogs)
+
+		idx, ok := selectWorklog(worklogs)
+		if !ok {
+			ui.PrintFarewell()
+			return nil
+		}
+
+		if err := r.changeWorklog(ctx, worklogs[idx]); err != nil {
+			ui.PrintError("Ошибка при изменении ворклога: " + describeError(err))
+		}
+	}
+}
+
+func selectWorklog(worklogs []jira.Worklog) (int, bool) {
+	options := make([]huh.Option[int], 0, len(worklogs)+1)
+	for i, wl := range worklogs {
+		label := fmt.Sprintf("%d. %s  %s  %s", i+1, worklogIssue(wl), timeparse.Format(wl.TimeSpentSeconds), wl.Comment)
+		options = append(options, huh.NewOption(label, i))
+	}
+	options = append(options, huh.NewOption("Готово", -1))
+
+	idx := -1
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[int]().
+				Title("Выберите ворклог").
+				Options(options...).
+				Value(&idx),
+		),
+	)
+	if err := form.Run(); err != nil || idx < 0 {
+		return 0, false
+	}
+	return idx, true
+}
+
+func (r *Runner) changeWorklog(ctx context.Context, wl jira.Worklog) error {
+	if worklogIssue(wl) == "" {
+		ui.PrintError("У ворклога не определена задача, изменить его нельзя.")
+		return nil
+	}
+
+	action := worklogBack
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[worklogAction]().
+				Title(fmt.Sprintf("%s, %s", worklogIssue(wl), timeparse.Format(wl.TimeSpentSeconds))).
+				Options(
+					huh.NewOption("Изменить время и комментарий", worklogEdit),
+					huh.NewOption("Удалить", worklogDelete),
+					huh.NewOption("Назад", worklogBack),
+				).
+				Value(&action),
+		),
+	)
+	if err := form.Run(); err != nil {
+		return nil
+	}
+
+	switch action {
+	case worklogEdit:
+		return r.editWorklog(ctx, wl)
+	case worklogDelete:
+		if !ui.ConfirmYesNo(fmt.Sprintf("Удалить ворклог %s (%s)?", worklogIssue(wl), timeparse.Format(wl.TimeSpentSeconds))) {
+			ui.PrintCancelled()
+			return nil
+		}
+		if err := r.sink.DeleteWorklog(ctx, worklogIssue(wl), wl.ID); err != nil {
+			return err
+		}
+		ui.PrintStatus("Ворклог удалён.")
+	}
+	return nil
+}
+
+func (r *Runner) editWorklog(ctx context.Context, wl jira.Worklog) error {
+	timeSpent := timeparse.Format(wl.TimeSpentSeconds)
+	comment := wl.Comment
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Время").
+				Value(&timeSpent).
+				Validate(func(s string) error {
+					if timeparse.Parse(s) <= 0 {
+						return fmt.Errorf("укажите время, например 2h 30m")
+					}
+					return nil
+				}),
+			huh.NewText().
+				Title("Комментарий").
+				Value(&comment),
+		).Title(worklogIssue(wl)),
+	)
+	if err := form.Run(); err != nil {
+		return nil
+	}
+
+	if err := r.sink.UpdateWorklog(ctx, worklogIssue(wl), wl.ID, timeparse.Parse(timeSpent), comment); err != nil {
+		return err
+	}
+	ui.PrintStatus("Ворклог обновлён.")
+	return nil
+}
+
+// worklogIssue returns the issue a worklog belongs to: its key, or its
+// numeric ID when the key is unknown. Jira accepts either in the path.
+func worklogIssue(wl jira.Worklog) string {
+	if wl.IssueKey != "" {
+		return wl.IssueKey
+	}
+	return wl.IssueID
+}
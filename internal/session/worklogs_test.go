@@ -0,0 +1,16 @@
+package session
+
+import (
+	"testing"
+
+	"go-secretary/internal/jira"
+)
+
+func TestWorklogIssue(t *testing.T) {
+	if got := worklogIssue(jira.Worklog{IssueKey: "A-1", IssueID: "10001"}); got != "A-1" {
+		t.Errorf("got %q, want A-1", got)
+	}
+	if got := worklogIssue(jira.Worklog{IssueID: "10001"}); got != "10001" {
+		t.Errorf("got %q, want 10001", got)
+	}
+}
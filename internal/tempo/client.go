@@ -0,0 +1,282 @@
+package tempo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-secretary/internal/jira"
+)
+
+const DefaultBaseURL = "https://api.tempo.io/4"
+
+const (
+	pageSize = 1000
+	// timeout bounds a whole Tempo request, including reading the response.
+	timeout = 30 * time.Second
+)
+
+// JiraResolver provides the Jira lookups Tempo needs: Tempo v4 identifies
+// issues by numeric ID and users by account ID, and keeps start times in the
+// user's local time.
+type JiraResolver interface {
+	IssueID(ctx context.Context, issueKey string) (string, error)
+	IssueKeys(ctx context.Context, ids []string) (map[string]string, error)
+	AccountID(ctx context.Context) (string, error)
+	Location(ctx context.Context) *time.Location
+}
+
+type Client struct {
+	baseURL    string
+	apiToken   string
+	jira       JiraResolver
+	attributes map[string]string
+	http       *http.Client
+}
+
+func NewClient(baseURL, apiToken string, jira JiraResolver) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return &Client{
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		apiToken: apiToken,
+		jira:     jira,
+		http:     &http.Client{Timeout: timeout},
+	}
+}
+
+// SetAttributes sets work attributes (e.g. "_Account_") attached to every new worklog.
+func (c *Client) SetAttributes(attributes map[string]string) {
+	c.attributes = attributes
+}
+
+// LogWork creates a Tempo worklog and returns its Tempo worklog ID. Unlike
+// the Jira client it does not attach the sj.session tag.
+func (c *Client) LogWork(ctx context.Context, issueKey string, timeSpentSeconds int, description string, started time.Time) (string, error) {
+	issueID, err := c.jira.IssueID(ctx, issueKey)
+	if err != nil {
+		return "", fmt.Errorf("resolve issue %s: %w", issueKey, err)
+	}
+	id, err := strconv.ParseInt(issueID, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid issue id %q: %w", issueID, err)
+	}
+	accountID, err := c.jira.AccountID(ctx)
+	if err != nil {
+		return "", fmt.Errorf("get current user: %w", err)
+	}
+
+	if started.IsZero() {
+		started = time.Now()
+	}
+	payload := worklogPayload{
+		IssueID:          id,
+		AuthorAccountID:  accountID,
+		TimeSpentSeconds: timeSpentSeconds,
+		StartDate:        started.Format("2006-01-02"),
+		StartTime:        started.Format("15:04:05"),
+		Description:      description,
+	}
+	keys := make([]string, 0, len(c.attributes))
+	for key := range c.attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		payload.Attributes = append(payload.Attributes, workAttribute{Key: key, Value: c.attributes[key]})
+	}
+
+	var created worklog
+	if err := c.doJSON(ctx, http.MethodPost, "/worklogs", nil, payload, &created); err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(created.TempoWorklogID, 10), nil
+}
+
+// UpdateWorklog changes the time spent and description of a Tempo worklog.
+// Tempo replaces the whole worklog on update, so its author, start and work
+// attributes are read first and sent back unchanged. The issue key is not
+// needed by Tempo and is accepted only to match the Jira client.
+func (c *Client) UpdateWorklog(ctx context.Context, issueKey, worklogID string, timeSpentSeconds int, comment string) error {
+	path := "/worklogs/" + url.PathEscape(worklogID)
+	var current worklog
+	if err := c.doJSON(ctx, http.MethodGet, path, nil, nil, &current); err != nil {
+		return fmt.Errorf("get worklog: %w", err)
+	}
+
+	payload := worklogUpdatePayload{
+		AuthorAccountID:  current.Author.AccountID,
+		TimeSpentSeconds: timeSpentSeconds,
+		StartDate:        current.StartDate,
+		StartTime:        current.StartTime,
+		Description:      comment,
+		Attributes:       current.Attributes.Values,
+	}
+	return c.doJSON(ctx, http.MethodPut, path, nil, payload, nil)
+}
+
+// DeleteWorklog deletes a Tempo worklog. The issue key is not needed by Tempo
+// and is accepted only to match the Jira client.
+func (c *Client) DeleteWorklog(ctx context.Context, issueKey, worklogID string) error {
+	return c.doJSON(ctx, http.MethodDelete, "/worklogs/"+url.PathEscape(worklogID), nil, nil, nil)
+}
+
+func (c *Client) GetLoggedSecondsForDateRange(ctx context.Context, startDate, endDate string) (map[string]int, error) {
+	worklogs, err := c.listWorklogs(ctx, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]int)
+	for _, wl := range worklogs {
+		result[wl.StartDate] += wl.TimeSpentSeconds
+	}
+	return result, nil
+}
+
+// ListMyWorklogs returns the current user's Tempo worklogs between startDate
+// and endDate inclusive. ID is the Tempo worklog ID, so the worklogs can be
+// passed back to UpdateWorklog and DeleteWorklog.
+func (c *Client) ListMyWorklogs(ctx context.Context, startDate, endDate string) ([]jira.Worklog, error) {
+	worklogs, err := c.listWorklogs(ctx, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	seen := make(map[int64]bool)
+	for _, wl := range worklogs {
+		if !seen[wl.Issue.ID] {
+			seen[wl.Issue.ID] = true
+			ids = append(ids, strconv.FormatInt(wl.Issue.ID, 10))
+		}
+	}
+	keys, err := c.jira.IssueKeys(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("resolve issue keys: %w", err)
+	}
+
+	loc := c.jira.Location(ctx)
+	result := make([]jira.Worklog, 0, len(worklogs))
+	for _, wl := range worklogs {
+		started, err := time.ParseInLocation("2006-01-02 15:04:05", wl.StartDate+" "+wl.StartTime, loc)
+		if err != nil {
+			return nil, fmt.Errorf("parse worklog %d start: %w", wl.TempoWorklogID, err)
+		}
+		issueID := strconv.FormatInt(wl.Issue.ID, 10)
+		result = append(result, jira.Worklog{
+			ID:               strconv.FormatInt(wl.TempoWorklogID, 10),
+			IssueID:          issueID,
+			IssueKey:         keys[issueID],
+			Comment:          wl.Description,
+			Started:          started,
+			TimeSpentSeconds: wl.TimeSpentSeconds,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Started.Before(result[j].Started) })
+	return result, nil
+}
+
+func (c *Client) listWorklogs(ctx context.Context, startDate, endDate string) ([]worklog, error) {
+	accountID, err := c.jira.AccountID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get current user: %w", err)
+	}
+
+	var worklogs []worklog
+	for offset := 0; ; offset += pageSize {
+		q := url.Values{}
+		q.Set("from", startDate)
+		q.Set("to", endDate)
+		q.Set("offset", strconv.Itoa(offset))
+		q.Set("limit", strconv.Itoa(pageSize))
+
+		var page worklogsResponse
+		if err := c.doJSON(ctx, http.MethodGet, "/worklogs/user/"+url.PathEscape(accountID), q, nil, &page); err != nil {
+			return nil, fmt.Errorf("list worklogs: %w", err)
+		}
+		worklogs = append(worklogs, page.Results...)
+
+		if len(page.Results) < pageSize || page.Metadata.Next == "" {
+			break
+		}
+	}
+	return worklogs, nil
+}
+
+// GetRequiredSecondsForDateRange returns required working time per day from
+// the user's Tempo work schedule, which accounts for holidays and part-time plans.
+func (c *Client) GetRequiredSecondsForDateRange(ctx context.Context, startDate, endDate string) (map[string]int, error) {
+	q := url.Values{}
+	q.Set("from", startDate)
+	q.Set("to", endDate)
+
+	var schedule scheduleResponse
+	if err := c.doJSON(ctx, http.MethodGet, "/user-schedule", q, nil, &schedule); err != nil {
+		return nil, fmt.Errorf("get user schedule: %w", err)
+	}
+
+	result := make(map[string]int, len(schedule.Results))
+	for _, day := range schedule.Results {
+		result[day.Date] = day.RequiredSeconds
+	}
+	return result, nil
+}
+
+func (c *Client) doJSON(ctx context.Context, method, path string, query url.Values, payload, out any) error {
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("marshal payload: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.apiToken)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("tempo request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		var er errorResponse
+		if json.Unmarshal(respBody, &er) == nil && len(er.Errors) > 0 {
+			return fmt.Errorf("tempo returned %d: %s", resp.StatusCode, er.Errors[0].Message)
+		}
+		return fmt.Errorf("tempo returned %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package tempo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type fakeJira struct{}
+
+func (fakeJira) IssueID(ctx context.Context, issueKey string) (string, error) { return "10042", nil }
+func (fakeJira) AccountID(ctx context.Context) (string, error)                { return "acc-1", nil }
+func (fakeJira) Location(ctx context.Context) *time.Location                  { return time.UTC }
+func (fakeJira) IssueKeys(ctx context.Context, ids []string) (map[string]string, error) {
+	return map[string]string{"10042": "PROJ-1"}, nil
+}
+
+func TestLogWork(t *testing.T) {
+	var got worklogPayload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/worklogs" || r.Header.Get("Authorization") != "Bearer tok" {
+			t.Errorf("unexpected request %s %s", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"tempoWorklogId":77}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "tok", fakeJira{})
+	c.SetAttributes(map[string]string{"_Account_": "ACC"})
+
+	loc := time.FixedZone("MSK", 3*3600)
+	started := time.Date(2025, 3, 4, 9, 0, 0, 0, loc)
+	id, err := c.LogWork(context.Background(), "PROJ-1", 5400, "review", started)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "77" {
+		t.Errorf("got worklog id %q, want 77", id)
+	}
+
+	if got.IssueID != 10042 || got.AuthorAccountID != "acc-1" || got.StartDate != "2025-03-04" || got.StartTime != "09:00:00" {
+		t.Errorf("unexpected payload %+v", got)
+	}
+	if len(got.Attributes) != 1 || got.Attributes[0].Value != "ACC" {
+		t.Errorf("unexpected attributes %+v", got.Attributes)
+	}
+}
+
+func TestGetRequiredSecondsForDateRange(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"results":[
+			{"date":"2025-03-07","requiredSeconds":28800,"type":"WORKING_DAY"},
+			{"date":"2025-03-08","requiredSeconds":0,"type":"NON_WORKING_DAY"}]}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "tok", fakeJira{})
+	got, err := c.GetRequiredSecondsForDateRange(context.Background(), "2025-03-07", "2025-03-08")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["2025-03-07"] != 28800 || got["2025-03-08"] != 0 {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestListMyWorklogs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/worklogs/user/acc-1" {
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"metadata":{"count":2},"results":[
+			{"tempoWorklogId":8,"issue":{"id":10042},"timeSpentSeconds":1800,"startDate":"2025-03-07","startTime":"14:00:00","description":"review"},
+			{"tempoWorklogId":7,"issue":{"id":10042},"timeSpentSeconds":3600,"startDate":"2025-03-07","startTime":"09:00:00","description":"standup"}]}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "tok", fakeJira{})
+	got, err := c.ListMyWorklogs(context.Background(), "2025-03-07", "2025-03-07")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d worklogs, want 2", len(got))
+	}
+	if wl := got[0]; wl.ID != "7" || wl.IssueKey != "PROJ-1" || wl.Comment != "standup" || wl.Started.Format("2006-01-02 15:04") != "2025-03-07 09:00" {
+		t.Errorf("unexpected worklog %+v", wl)
+	}
+}
+
+func TestUpdateWorklogKeepsStartAndAttributes(t *testing.T) {
+	var got worklogUpdatePayload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/worklogs/7" {
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		switch r.Method {
+		case http.MethodGet:
+			w.Write([]byte(`{"tempoWorklogId":7,"issue":{"id":10042},"author":{"accountId":"acc-1"},
+				"timeSpentSeconds":3600,"startDate":"2025-03-07","startTime":"09:00:00","description":"standup",
+				"attributes":{"values":[{"key":"_Account_","value":"ACC"}]}}`))
+		case http.MethodPut:
+			json.NewDecoder(r.Body).Decode(&got)
+			w.Write([]byte(`{"tempoWorklogId":7}`))
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "tok", fakeJira{})
+	if err := c.UpdateWorklog(context.Background(), "PROJ-1", "7", 1800, "daily"); err != nil {
+		t.Fatal(err)
+	}
+	want := worklogUpdatePayload{
+		AuthorAccountID:  "acc-1",
+		TimeSpentSeconds: 1800,
+		StartDate:        "2025-03-07",
+		StartTime:        "09:00:00",
+		Description:      "daily",
+		Attributes:       []workAttribute{{Key: "_Account_", Value: "ACC"}},
+	}
+	if got.AuthorAccountID != want.AuthorAccountID || got.TimeSpentSeconds != want.TimeSpentSeconds ||
+		got.StartDate != want.StartDate || got.StartTime != want.StartTime || got.Description != want.Description ||
+		len(got.Attributes) != 1 || got.Attributes[0] != want.Attributes[0] {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,76 @@
+package tempo
+
+type worklogPayload struct {
+	IssueID          int64           `json:"issueId"`
+	AuthorAccountID  string          `json:"authorAccountId"`
+	TimeSpentSeconds int             `json:"timeSpentSeconds"`
+	StartDate        string          `json:"startDate"`
+	StartTime        string          `json:"startTime"`
+	Description      string          `json:"description"`
+	Attributes       []workAttribute `json:"attributes,omitempty"`
+}
+
+type worklogUpdatePayload struct {
+	AuthorAccountID  string          `json:"authorAccountId"`
+	TimeSpentSeconds int             `json:"timeSpentSeconds"`
+	StartDate        string          `json:"startDate"`
+	StartTime        string          `json:"startTime"`
+	Description      string          `json:"description"`
+	Attributes       []workAttribute `json:"attributes,omitempty"`
+}
+
+type workAttribute struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type worklogsResponse struct {
+	Metadata pageMetadata `json:"metadata"`
+	Results  []worklog    `json:"results"`
+}
+
+type pageMetadata struct {
+	Count  int    `json:"count"`
+	Offset int    `json:"offset"`
+	Limit  int    `json:"limit"`
+	Next   string `json:"next"`
+}
+
+type worklog struct {
+	TempoWorklogID   int64             `json:"tempoWorklogId"`
+	Issue            issue             `json:"issue"`
+	Author           author            `json:"author"`
+	TimeSpentSeconds int               `json:"timeSpentSeconds"`
+	StartDate        string            `json:"startDate"`
+	StartTime        string            `json:"startTime"`
+	Description      string            `json:"description"`
+	Attributes       worklogAttributes `json:"attributes"`
+}
+
+type author struct {
+	AccountID string `json:"accountId"`
+}
+
+type worklogAttributes struct {
+	Values []workAttribute `json:"values"`
+}
+
+type issue struct {
+	ID int64 `json:"id"`
+}
+
+type scheduleResponse struct {
+	Results []scheduleDay `json:"results"`
+}
+
+type scheduleDay struct {
+	Date            string `json:"date"`
+	RequiredSeconds int    `json:"requiredSeconds"`
+	Type            string `json:"type"`
+}
+
+type errorResponse struct {
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
@@ -1,6 +1,7 @@
 package timeparse
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 )
@@ -36,3 +37,16 @@ func Parse(input string) int {
 
 	return totalSeconds
 }
+
+// Format converts seconds into the "2h 30m" form accepted by Parse.
+func Format(seconds int) string {
+	h := seconds / 3600
+	m := (seconds % 3600) / 60
+	if h > 0 && m > 0 {
+		return fmt.Sprintf("%dh %dm", h, m)
+	}
+	if h > 0 {
+		return fmt.Sprintf("%dh", h)
+	}
+	return fmt.Sprintf("%dm", m)
+}
@@ -29,3 +29,25 @@ func TestParse(t *testing.T) {
 		})
 	}
 }
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		seconds int
+		want    string
+	}{
+		{9000, "2h 30m"},
+		{7200, "2h"},
+		{1800, "30m"},
+		{0, "0m"},
+	}
+
+	for _, tt := range tests {
+		got := Format(tt.seconds)
+		if got != tt.want {
+			t.Errorf("Format(%d) = %q, want %q", tt.seconds, got, tt.want)
+		}
+		if Parse(got) != tt.seconds {
+			t.Errorf("Parse(Format(%d)) = %d", tt.seconds, Parse(got))
+		}
+	}
+}
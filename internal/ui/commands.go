@@ -19,6 +19,7 @@ type CommandDef struct {
 var AvailableCommands = []CommandDef{
 	{Name: "/help", Description: "Показать список команд"},
 	{Name: "/model", Description: "Сменить модель Gemini"},
+	{Name: "/undo", Description: "Отменить отправленную сессию"},
 	{Name: "/config", Description: "Открыть настройки"},
 	{Name: "/clear", Description: "Очистить экран"},
 	{Name: "/exit", Description: "Выйти из программы"},
@@ -5,6 +5,7 @@ import (
 
 	"go-secretary/internal/gemini"
 	"go-secretary/internal/jira"
+	"go-secretary/internal/timeparse"
 
 	"github.com/pterm/pterm"
 )
@@ -36,30 +37,75 @@ func PrintIssuesTable(issues []jira.Issue) {
 	pterm.Println()
 }
 
-func PrintSummary(logs []gemini.ParsedWorkLog) {
+// PrintSummary renders the worklogs about to be sent. If notes is non-nil,
+// a check column shows notes[i] for row i, or OK when it is empty.
+func PrintSummary(logs []gemini.ParsedWorkLog, notes []string) {
 	pterm.Println()
 	pterm.DefaultSection.WithStyle(pterm.NewStyle(pterm.FgCyan, pterm.Bold)).Println("Итоговая сводка")
 
-	tableData := pterm.TableData{
-		{"Задача", "Время", "Описание"},
+	header := []string{"Задача", "Время", "Описание"}
+	if notes != nil {
+		header = append(header, "Проверка")
 	}
+	tableData := pterm.TableData{header}
 
 	totalSeconds := 0
-	for _, log := range logs {
+	for i, log := range logs {
 		hours := float64(log.TimeSeconds) / 3600.0
 		totalSeconds += log.TimeSeconds
-		tableData = append(tableData, []string{
+		row := []string{
 			pterm.FgCyan.Sprint(log.IssueKey),
 			pterm.FgYellow.Sprintf("%.1fч", hours),
 			log.Description,
-		})
+		}
+		if notes != nil {
+			if notes[i] == "" {
+				row = append(row, pterm.FgGreen.Sprint("OK"))
+			} else {
+				row = append(row, pterm.FgRed.Sprint(notes[i]))
+			}
+		}
+		tableData = append(tableData, row)
 	}
 
 	totalHours := float64(totalSeconds) / 3600.0
-	tableData = append(tableData, []string{
+	total := []string{
 		pterm.Bold.Sprint("ИТОГО"),
 		pterm.Bold.Sprint(pterm.FgYellow.Sprintf("%.1fч", totalHours)),
 		"",
+	}
+	if notes != nil {
+		total = append(total, "")
+	}
+	tableData = append(tableData, total)
+
+	pterm.DefaultTable.WithHasHeader().WithBoxed().WithData(tableData).Render()
+	pterm.Println()
+}
+
+func PrintWorklogsTable(worklogs []jira.Worklog) {
+	tableData := pterm.TableData{
+		{"#", "Задача", "Начало", "Время", "Комментарий"},
+	}
+
+	totalSeconds := 0
+	for i, wl := range worklogs {
+		totalSeconds += wl.TimeSpentSeconds
+		tableData = append(tableData, []string{
+			fmt.Sprintf("%d", i+1),
+			pterm.FgCyan.Sprint(wl.IssueKey),
+			wl.Started.Format("2006-01-02 15:04"),
+			pterm.FgYellow.Sprint(timeparse.Format(wl.TimeSpentSeconds)),
+			wl.Comment,
+		})
+	}
+
+	tableData = append(tableData, []string{
+		"",
+		pterm.Bold.Sprint("ИТОГО"),
+		"",
+		pterm.Bold.Sprint(pterm.FgYellow.Sprint(timeparse.Format(totalSeconds))),
+		"",
 	})
 
 	pterm.DefaultTable.WithHasHeader().WithBoxed().WithData(tableData).Render()
@@ -74,6 +120,35 @@ func PrintLogResult(issueKey string, success bool) {
 	}
 }
 
+// PrintSubmissionReport tells how many of the rows were submitted.
+func PrintSubmissionReport(sent, total int) {
+	pterm.Println()
+	if sent == total {
+		pterm.Success.Printfln("Отправлено: %d из %d", sent, total)
+		return
+	}
+	pterm.Warning.Printfln("Отправлено: %d из %d, с ошибкой: %d", sent, total, total-sent)
+}
+
+func PrintFailedWorklogs(rows []FailedWorklog) {
+	tableData := pterm.TableData{
+		{"#", "Задача", "Дата", "Время", "Описание", "Ошибка"},
+	}
+	for i, row := range rows {
+		tableData = append(tableData, []string{
+			fmt.Sprintf("%d", i+1),
+			pterm.FgCyan.Sprint(row.IssueKey),
+			row.Date,
+			pterm.FgYellow.Sprint(timeparse.Format(row.TimeSeconds)),
+			row.Description,
+			pterm.FgRed.Sprint(row.Error),
+		})
+	}
+
+	pterm.DefaultTable.WithHasHeader().WithBoxed().WithData(tableData).Render()
+	pterm.Println()
+}
+
 func PrintNoIssues() {
 	pterm.Warning.Println("Не найдено задач в статусе 'In Progress'")
 	pterm.Println(pterm.Gray("Проверь, что у тебя есть задачи в работе в Jira."))
@@ -93,14 +93,33 @@ func IsExitCommand(input string) bool {
 	return false
 }
 
-func ReadDateRange() (startDate, endDate string, err error) {
-	validateDate := func(s string) error {
-		if _, err := time.Parse("2006-01-02", s); err != nil {
-			return fmt.Errorf("неверный формат даты, используйте ГГГГ-ММ-ДД")
-		}
-		return nil
+func validateDate(s string) error {
+	if _, err := time.Parse("2006-01-02", s); err != nil {
+		return fmt.Errorf("неверный формат даты, используйте ГГГГ-ММ-ДД")
+	}
+	return nil
+}
+
+// ReadDate asks for a single date, pre-filled with defaultDate.
+func ReadDate(defaultDate string) (string, error) {
+	date := defaultDate
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Дата (ГГГГ-ММ-ДД)").
+				Value(&date).
+				Validate(validateDate),
+		),
+	)
+
+	if err := form.Run(); err != nil {
+		return "", fmt.Errorf("ввод даты: %w", err)
 	}
 
+	return date, nil
+}
+
+func ReadDateRange() (startDate, endDate string, err error) {
 	form := huh.NewForm(
 		huh.NewGroup(
 			huh.NewInput().
@@ -1,8 +1,18 @@
 package ui
 
 type DayStatus struct {
-	Date          string
-	Weekday       string
-	LoggedSeconds int
-	Filled        bool
+	Date            string
+	Weekday         string
+	LoggedSeconds   int
+	RequiredSeconds int
+	Filled          bool
+}
+
+// FailedWorklog is a row that could not be submitted, with the reason.
+type FailedWorklog struct {
+	IssueKey    string
+	Date        string
+	TimeSeconds int
+	Description string
+	Error       string
 }